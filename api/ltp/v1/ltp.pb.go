@@ -0,0 +1,762 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v3.5.1-go
+// source: api/ltp/v1/ltp.proto
+
+package ltpv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Amounts and volumes are decimal strings so no precision is lost in transit.
+type LTP struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Pair      string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Amount    string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Error     string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source    string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Volume    string                 `protobuf:"bytes,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	// Set when the price was triangulated through other markets; timestamp is then the oldest leg's.
+	Legs []*Leg `protobuf:"bytes,7,rep,name=legs,proto3" json:"legs,omitempty"`
+	// Set when an expired price was served while a refresh runs in the background.
+	Stale         bool  `protobuf:"varint,8,opt,name=stale,proto3" json:"stale,omitempty"`
+	AgeMs         int64 `protobuf:"varint,9,opt,name=age_ms,json=ageMs,proto3" json:"age_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LTP) Reset() {
+	*x = LTP{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LTP) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LTP) ProtoMessage() {}
+
+func (x *LTP) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LTP.ProtoReflect.Descriptor instead.
+func (*LTP) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LTP) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *LTP) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *LTP) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *LTP) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LTP) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *LTP) GetVolume() string {
+	if x != nil {
+		return x.Volume
+	}
+	return ""
+}
+
+func (x *LTP) GetLegs() []*Leg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *LTP) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *LTP) GetAgeMs() int64 {
+	if x != nil {
+		return x.AgeMs
+	}
+	return 0
+}
+
+type Leg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pair          string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Inverted      bool                   `protobuf:"varint,3,opt,name=inverted,proto3" json:"inverted,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source        string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Leg) Reset() {
+	*x = Leg{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leg) ProtoMessage() {}
+
+func (x *Leg) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leg.ProtoReflect.Descriptor instead.
+func (*Leg) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Leg) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *Leg) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Leg) GetInverted() bool {
+	if x != nil {
+		return x.Inverted
+	}
+	return false
+}
+
+func (x *Leg) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Leg) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetLTPRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pair  string                 `protobuf:"bytes,1,opt,name=pair,proto3" json:"pair,omitempty"`
+	// Optional provider name; empty uses the default provider.
+	Source        string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLTPRequest) Reset() {
+	*x = GetLTPRequest{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLTPRequest) ProtoMessage() {}
+
+func (x *GetLTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLTPRequest.ProtoReflect.Descriptor instead.
+func (*GetLTPRequest) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetLTPRequest) GetPair() string {
+	if x != nil {
+		return x.Pair
+	}
+	return ""
+}
+
+func (x *GetLTPRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetLTPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ltp           *LTP                   `protobuf:"bytes,1,opt,name=ltp,proto3" json:"ltp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLTPResponse) Reset() {
+	*x = GetLTPResponse{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLTPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLTPResponse) ProtoMessage() {}
+
+func (x *GetLTPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLTPResponse.ProtoReflect.Descriptor instead.
+func (*GetLTPResponse) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLTPResponse) GetLtp() *LTP {
+	if x != nil {
+		return x.Ltp
+	}
+	return nil
+}
+
+type GetLTPsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty means every configured pair.
+	Pairs         []string `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	Source        string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLTPsRequest) Reset() {
+	*x = GetLTPsRequest{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLTPsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLTPsRequest) ProtoMessage() {}
+
+func (x *GetLTPsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLTPsRequest.ProtoReflect.Descriptor instead.
+func (*GetLTPsRequest) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetLTPsRequest) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *GetLTPsRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetLTPsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ltps          []*LTP                 `protobuf:"bytes,1,rep,name=ltps,proto3" json:"ltps,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLTPsResponse) Reset() {
+	*x = GetLTPsResponse{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLTPsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLTPsResponse) ProtoMessage() {}
+
+func (x *GetLTPsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLTPsResponse.ProtoReflect.Descriptor instead.
+func (*GetLTPsResponse) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetLTPsResponse) GetLtps() []*LTP {
+	if x != nil {
+		return x.Ltps
+	}
+	return nil
+}
+
+type ListPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPairsRequest) Reset() {
+	*x = ListPairsRequest{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPairsRequest) ProtoMessage() {}
+
+func (x *ListPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPairsRequest.ProtoReflect.Descriptor instead.
+func (*ListPairsRequest) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{6}
+}
+
+type ListPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []string               `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	Sources       []string               `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPairsResponse) Reset() {
+	*x = ListPairsResponse{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPairsResponse) ProtoMessage() {}
+
+func (x *ListPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPairsResponse.ProtoReflect.Descriptor instead.
+func (*ListPairsResponse) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPairsResponse) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *ListPairsResponse) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+type WatchLTPRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty means every pair.
+	Pairs []string `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Superseded by resume_after: a bare id does not say which epoch it came from, so it always resets.
+	//
+	// Deprecated: Marked as deprecated in api/ltp/v1/ltp.proto.
+	LastEventId uint64 `protobuf:"varint,2,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"`
+	// Resume after this event_id, replaying recent updates that were missed.
+	ResumeAfter   string `protobuf:"bytes,3,opt,name=resume_after,json=resumeAfter,proto3" json:"resume_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLTPRequest) Reset() {
+	*x = WatchLTPRequest{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLTPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLTPRequest) ProtoMessage() {}
+
+func (x *WatchLTPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLTPRequest.ProtoReflect.Descriptor instead.
+func (*WatchLTPRequest) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchLTPRequest) GetPairs() []string {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in api/ltp/v1/ltp.proto.
+func (x *WatchLTPRequest) GetLastEventId() uint64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+func (x *WatchLTPRequest) GetResumeAfter() string {
+	if x != nil {
+		return x.ResumeAfter
+	}
+	return ""
+}
+
+type LTPUpdate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Ltp   *LTP                   `protobuf:"bytes,2,opt,name=ltp,proto3" json:"ltp,omitempty"`
+	// Identifies the server process that numbered id; ids restart in a new epoch.
+	Epoch string `protobuf:"bytes,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	// Pass as WatchLTPRequest.resume_after to continue after this update.
+	EventId string `protobuf:"bytes,4,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Set on a first update without ltp when the resume point cannot be replayed without a gap; the client
+	// should reload current prices.
+	Resync        bool `protobuf:"varint,5,opt,name=resync,proto3" json:"resync,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LTPUpdate) Reset() {
+	*x = LTPUpdate{}
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LTPUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LTPUpdate) ProtoMessage() {}
+
+func (x *LTPUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_ltp_v1_ltp_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LTPUpdate.ProtoReflect.Descriptor instead.
+func (*LTPUpdate) Descriptor() ([]byte, []int) {
+	return file_api_ltp_v1_ltp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LTPUpdate) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *LTPUpdate) GetLtp() *LTP {
+	if x != nil {
+		return x.Ltp
+	}
+	return nil
+}
+
+func (x *LTPUpdate) GetEpoch() string {
+	if x != nil {
+		return x.Epoch
+	}
+	return ""
+}
+
+func (x *LTPUpdate) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *LTPUpdate) GetResync() bool {
+	if x != nil {
+		return x.Resync
+	}
+	return false
+}
+
+var File_api_ltp_v1_ltp_proto protoreflect.FileDescriptor
+
+const file_api_ltp_v1_ltp_proto_rawDesc = "" +
+	"\n" +
+	"\x14api/ltp/v1/ltp.proto\x12\x06ltp.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xff\x01\n" +
+	"\x03LTP\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\tR\x06volume\x12\x1f\n" +
+	"\x04legs\x18\a \x03(\v2\v.ltp.v1.LegR\x04legs\x12\x14\n" +
+	"\x05stale\x18\b \x01(\bR\x05stale\x12\x15\n" +
+	"\x06age_ms\x18\t \x01(\x03R\x05ageMs\"\x9f\x01\n" +
+	"\x03Leg\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x1a\n" +
+	"\binverted\x18\x03 \x01(\bR\binverted\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\";\n" +
+	"\rGetLTPRequest\x12\x12\n" +
+	"\x04pair\x18\x01 \x01(\tR\x04pair\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"/\n" +
+	"\x0eGetLTPResponse\x12\x1d\n" +
+	"\x03ltp\x18\x01 \x01(\v2\v.ltp.v1.LTPR\x03ltp\">\n" +
+	"\x0eGetLTPsRequest\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"2\n" +
+	"\x0fGetLTPsResponse\x12\x1f\n" +
+	"\x04ltps\x18\x01 \x03(\v2\v.ltp.v1.LTPR\x04ltps\"\x12\n" +
+	"\x10ListPairsRequest\"C\n" +
+	"\x11ListPairsResponse\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\x12\x18\n" +
+	"\asources\x18\x02 \x03(\tR\asources\"r\n" +
+	"\x0fWatchLTPRequest\x12\x14\n" +
+	"\x05pairs\x18\x01 \x03(\tR\x05pairs\x12&\n" +
+	"\rlast_event_id\x18\x02 \x01(\x04B\x02\x18\x01R\vlastEventId\x12!\n" +
+	"\fresume_after\x18\x03 \x01(\tR\vresumeAfter\"\x83\x01\n" +
+	"\tLTPUpdate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\x03ltp\x18\x02 \x01(\v2\v.ltp.v1.LTPR\x03ltp\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\tR\x05epoch\x12\x19\n" +
+	"\bevent_id\x18\x04 \x01(\tR\aeventId\x12\x16\n" +
+	"\x06resync\x18\x05 \x01(\bR\x06resync2\xfd\x01\n" +
+	"\n" +
+	"LTPService\x127\n" +
+	"\x06GetLTP\x12\x15.ltp.v1.GetLTPRequest\x1a\x16.ltp.v1.GetLTPResponse\x12:\n" +
+	"\aGetLTPs\x12\x16.ltp.v1.GetLTPsRequest\x1a\x17.ltp.v1.GetLTPsResponse\x12@\n" +
+	"\tListPairs\x12\x18.ltp.v1.ListPairsRequest\x1a\x19.ltp.v1.ListPairsResponse\x128\n" +
+	"\bWatchLTP\x12\x17.ltp.v1.WatchLTPRequest\x1a\x11.ltp.v1.LTPUpdate0\x01B:Z8github.com/FrancoRivero2025/go-exercise/api/ltp/v1;ltpv1b\x06proto3"
+
+var (
+	file_api_ltp_v1_ltp_proto_rawDescOnce sync.Once
+	file_api_ltp_v1_ltp_proto_rawDescData []byte
+)
+
+func file_api_ltp_v1_ltp_proto_rawDescGZIP() []byte {
+	file_api_ltp_v1_ltp_proto_rawDescOnce.Do(func() {
+		file_api_ltp_v1_ltp_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_ltp_v1_ltp_proto_rawDesc), len(file_api_ltp_v1_ltp_proto_rawDesc)))
+	})
+	return file_api_ltp_v1_ltp_proto_rawDescData
+}
+
+var file_api_ltp_v1_ltp_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_api_ltp_v1_ltp_proto_goTypes = []any{
+	(*LTP)(nil),                   // 0: ltp.v1.LTP
+	(*Leg)(nil),                   // 1: ltp.v1.Leg
+	(*GetLTPRequest)(nil),         // 2: ltp.v1.GetLTPRequest
+	(*GetLTPResponse)(nil),        // 3: ltp.v1.GetLTPResponse
+	(*GetLTPsRequest)(nil),        // 4: ltp.v1.GetLTPsRequest
+	(*GetLTPsResponse)(nil),       // 5: ltp.v1.GetLTPsResponse
+	(*ListPairsRequest)(nil),      // 6: ltp.v1.ListPairsRequest
+	(*ListPairsResponse)(nil),     // 7: ltp.v1.ListPairsResponse
+	(*WatchLTPRequest)(nil),       // 8: ltp.v1.WatchLTPRequest
+	(*LTPUpdate)(nil),             // 9: ltp.v1.LTPUpdate
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_api_ltp_v1_ltp_proto_depIdxs = []int32{
+	10, // 0: ltp.v1.LTP.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 1: ltp.v1.LTP.legs:type_name -> ltp.v1.Leg
+	10, // 2: ltp.v1.Leg.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 3: ltp.v1.GetLTPResponse.ltp:type_name -> ltp.v1.LTP
+	0,  // 4: ltp.v1.GetLTPsResponse.ltps:type_name -> ltp.v1.LTP
+	0,  // 5: ltp.v1.LTPUpdate.ltp:type_name -> ltp.v1.LTP
+	2,  // 6: ltp.v1.LTPService.GetLTP:input_type -> ltp.v1.GetLTPRequest
+	4,  // 7: ltp.v1.LTPService.GetLTPs:input_type -> ltp.v1.GetLTPsRequest
+	6,  // 8: ltp.v1.LTPService.ListPairs:input_type -> ltp.v1.ListPairsRequest
+	8,  // 9: ltp.v1.LTPService.WatchLTP:input_type -> ltp.v1.WatchLTPRequest
+	3,  // 10: ltp.v1.LTPService.GetLTP:output_type -> ltp.v1.GetLTPResponse
+	5,  // 11: ltp.v1.LTPService.GetLTPs:output_type -> ltp.v1.GetLTPsResponse
+	7,  // 12: ltp.v1.LTPService.ListPairs:output_type -> ltp.v1.ListPairsResponse
+	9,  // 13: ltp.v1.LTPService.WatchLTP:output_type -> ltp.v1.LTPUpdate
+	10, // [10:14] is the sub-list for method output_type
+	6,  // [6:10] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_ltp_v1_ltp_proto_init() }
+func file_api_ltp_v1_ltp_proto_init() {
+	if File_api_ltp_v1_ltp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_ltp_v1_ltp_proto_rawDesc), len(file_api_ltp_v1_ltp_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_ltp_v1_ltp_proto_goTypes,
+		DependencyIndexes: file_api_ltp_v1_ltp_proto_depIdxs,
+		MessageInfos:      file_api_ltp_v1_ltp_proto_msgTypes,
+	}.Build()
+	File_api_ltp_v1_ltp_proto = out.File
+	file_api_ltp_v1_ltp_proto_goTypes = nil
+	file_api_ltp_v1_ltp_proto_depIdxs = nil
+}
@@ -0,0 +1,245 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.5.1-go
+// source: api/ltp/v1/ltp.proto
+
+package ltpv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LTPService_GetLTP_FullMethodName    = "/ltp.v1.LTPService/GetLTP"
+	LTPService_GetLTPs_FullMethodName   = "/ltp.v1.LTPService/GetLTPs"
+	LTPService_ListPairs_FullMethodName = "/ltp.v1.LTPService/ListPairs"
+	LTPService_WatchLTP_FullMethodName  = "/ltp.v1.LTPService/WatchLTP"
+)
+
+// LTPServiceClient is the client API for LTPService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LTPService serves the same last traded prices as the REST API.
+type LTPServiceClient interface {
+	GetLTP(ctx context.Context, in *GetLTPRequest, opts ...grpc.CallOption) (*GetLTPResponse, error)
+	GetLTPs(ctx context.Context, in *GetLTPsRequest, opts ...grpc.CallOption) (*GetLTPsResponse, error)
+	ListPairs(ctx context.Context, in *ListPairsRequest, opts ...grpc.CallOption) (*ListPairsResponse, error)
+	// WatchLTP streams every new price for the requested pairs until the client cancels.
+	WatchLTP(ctx context.Context, in *WatchLTPRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LTPUpdate], error)
+}
+
+type lTPServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLTPServiceClient(cc grpc.ClientConnInterface) LTPServiceClient {
+	return &lTPServiceClient{cc}
+}
+
+func (c *lTPServiceClient) GetLTP(ctx context.Context, in *GetLTPRequest, opts ...grpc.CallOption) (*GetLTPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLTPResponse)
+	err := c.cc.Invoke(ctx, LTPService_GetLTP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lTPServiceClient) GetLTPs(ctx context.Context, in *GetLTPsRequest, opts ...grpc.CallOption) (*GetLTPsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLTPsResponse)
+	err := c.cc.Invoke(ctx, LTPService_GetLTPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lTPServiceClient) ListPairs(ctx context.Context, in *ListPairsRequest, opts ...grpc.CallOption) (*ListPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPairsResponse)
+	err := c.cc.Invoke(ctx, LTPService_ListPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lTPServiceClient) WatchLTP(ctx context.Context, in *WatchLTPRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LTPUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LTPService_ServiceDesc.Streams[0], LTPService_WatchLTP_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLTPRequest, LTPUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LTPService_WatchLTPClient = grpc.ServerStreamingClient[LTPUpdate]
+
+// LTPServiceServer is the server API for LTPService service.
+// All implementations must embed UnimplementedLTPServiceServer
+// for forward compatibility.
+//
+// LTPService serves the same last traded prices as the REST API.
+type LTPServiceServer interface {
+	GetLTP(context.Context, *GetLTPRequest) (*GetLTPResponse, error)
+	GetLTPs(context.Context, *GetLTPsRequest) (*GetLTPsResponse, error)
+	ListPairs(context.Context, *ListPairsRequest) (*ListPairsResponse, error)
+	// WatchLTP streams every new price for the requested pairs until the client cancels.
+	WatchLTP(*WatchLTPRequest, grpc.ServerStreamingServer[LTPUpdate]) error
+	mustEmbedUnimplementedLTPServiceServer()
+}
+
+// UnimplementedLTPServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLTPServiceServer struct{}
+
+func (UnimplementedLTPServiceServer) GetLTP(context.Context, *GetLTPRequest) (*GetLTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLTP not implemented")
+}
+func (UnimplementedLTPServiceServer) GetLTPs(context.Context, *GetLTPsRequest) (*GetLTPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLTPs not implemented")
+}
+func (UnimplementedLTPServiceServer) ListPairs(context.Context, *ListPairsRequest) (*ListPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPairs not implemented")
+}
+func (UnimplementedLTPServiceServer) WatchLTP(*WatchLTPRequest, grpc.ServerStreamingServer[LTPUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLTP not implemented")
+}
+func (UnimplementedLTPServiceServer) mustEmbedUnimplementedLTPServiceServer() {}
+func (UnimplementedLTPServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeLTPServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LTPServiceServer will
+// result in compilation errors.
+type UnsafeLTPServiceServer interface {
+	mustEmbedUnimplementedLTPServiceServer()
+}
+
+func RegisterLTPServiceServer(s grpc.ServiceRegistrar, srv LTPServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLTPServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LTPService_ServiceDesc, srv)
+}
+
+func _LTPService_GetLTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LTPServiceServer).GetLTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LTPService_GetLTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LTPServiceServer).GetLTP(ctx, req.(*GetLTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LTPService_GetLTPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLTPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LTPServiceServer).GetLTPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LTPService_GetLTPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LTPServiceServer).GetLTPs(ctx, req.(*GetLTPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LTPService_ListPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LTPServiceServer).ListPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LTPService_ListPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LTPServiceServer).ListPairs(ctx, req.(*ListPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LTPService_WatchLTP_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLTPRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LTPServiceServer).WatchLTP(m, &grpc.GenericServerStream[WatchLTPRequest, LTPUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LTPService_WatchLTPServer = grpc.ServerStreamingServer[LTPUpdate]
+
+// LTPService_ServiceDesc is the grpc.ServiceDesc for LTPService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LTPService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ltp.v1.LTPService",
+	HandlerType: (*LTPServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLTP",
+			Handler:    _LTPService_GetLTP_Handler,
+		},
+		{
+			MethodName: "GetLTPs",
+			Handler:    _LTPService_GetLTPs_Handler,
+		},
+		{
+			MethodName: "ListPairs",
+			Handler:    _LTPService_ListPairs_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLTP",
+			Handler:       _LTPService_WatchLTP_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api/ltp/v1/ltp.proto",
+}
@@ -2,17 +2,26 @@ package main
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/config"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/binance"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/bitstamp"
 	"github.com/FrancoRivero2025/go-exercise/internal/adapters/cache"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/circuitbreaker"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/cluster"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/coinbase"
+	grpcapi "github.com/FrancoRivero2025/go-exercise/internal/adapters/grpc"
 	httpapi "github.com/FrancoRivero2025/go-exercise/internal/adapters/http"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/history"
 	"github.com/FrancoRivero2025/go-exercise/internal/adapters/kraken"
 	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
 	"github.com/FrancoRivero2025/go-exercise/internal/adapters/refresher"
@@ -20,6 +29,8 @@ import (
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
 )
 
 func main() {
@@ -40,6 +51,7 @@ func main() {
 	useRedis := os.Getenv("USE_REDIS") == "true"
 
 	var c domain.Cache
+	var redisClient redis.UniversalClient
 	if useRedis {
 		redisTTL := os.Getenv("REDIS_TTL")
 		if redisTTL == "" {
@@ -50,38 +62,224 @@ func main() {
 		if err != nil {
 			logger.Fatal("invalid REDIS_TTL: %v", err)
 		}
-
-		redisDB := 0
-		if dbStr := os.Getenv("REDIS_DB"); dbStr != "" {
-			if db, err := strconv.Atoi(dbStr); err == nil {
-				redisDB = db
-			}
+		// Entries must outlive the stale window to be served while they refresh.
+		if maxStale := time.Duration(cfg.Cache.MaxStaleness) * time.Second; cfg.Cache.StaleWhileRevalidate && ttl < maxStale {
+			ttl = maxStale
 		}
 
-		redisCache := cache.NewRedisCache(
-			os.Getenv("REDIS_ADDR"),
-			os.Getenv("REDIS_PASSWORD"),
-			redisDB,
-			ttl,
-		)
+		redisClient, err = cache.NewRedisClient(redisOptions(cfg.Redis))
 		if err != nil {
 			logger.Fatal("failed to create Redis cache: %v", err)
 		}
-		c = redisCache
-		logger.Info("Using Redis cache")
+		redisCache := cache.NewRedisCacheWithClient(redisClient, ttl)
+		if cfg.Cache.L1.Enabled {
+			l1 := cache.NewInMemoryCache(time.Duration(cfg.Cache.L1.TTL) * time.Second)
+			l1.SetMaxEntries(cfg.Cache.L1.MaxEntries)
+			c = cache.NewTieredCache(l1, redisCache)
+			logger.Info("Using Redis cache behind an in-process L1")
+		} else {
+			c = redisCache
+			logger.Info("Using Redis cache")
+		}
 	} else {
-		c = cache.NewInMemoryCache(time.Duration(cfg.Cache.TTL) * time.Second)
+		retention := time.Duration(cfg.Cache.TTL) * time.Second
+		if cfg.Cache.StaleWhileRevalidate {
+			retention = time.Duration(cfg.Cache.MaxStaleness) * time.Second
+		}
+		c = cache.NewInMemoryCache(retention)
 		logger.Info("Using in-memory cache")
 	}
 
 	krakenClient := kraken.NewClient(cfg.Kraken.URL, 15)
+	krakenClient.SetPairsRefreshInterval(time.Duration(cfg.Kraken.PairsRefresh) * time.Second)
+	krakenClient.SetRateLimit(cfg.Kraken.RateLimit.RequestsPerSecond, cfg.Kraken.RateLimit.Burst,
+		time.Duration(cfg.Kraken.RateLimit.MaxWait)*time.Millisecond)
+	if err := krakenClient.LoadAssetPairs(ctx); err != nil {
+		logger.Warn("Failed to load Kraken asset pairs, skipping pair validation: %v", err)
+	} else {
+		var supported config.PairCatalog = krakenClient
+		if cfg.CrossRate.Enabled {
+			supported = application.DerivableCatalog{Catalog: krakenClient, MaxLegs: cfg.CrossRate.MaxLegs}
+		}
+		cfg.Validate(supported)
+	}
+	krakenClient.StartPairsRefresh()
+	defer krakenClient.StopPairsRefresh()
+
+	var provider application.MarketDataProvider = krakenClient
+	if cfg.Kraken.Breaker.Enabled {
+		provider = circuitbreaker.New(kraken.Source, krakenClient, circuitbreaker.Options{
+			FailureThreshold: cfg.Kraken.Breaker.FailureThreshold,
+			Cooldown:         time.Duration(cfg.Kraken.Breaker.Cooldown) * time.Second,
+		})
+		logger.Info("Kraken circuit breaker enabled")
+	}
+
+	var stream *kraken.StreamClient
+	if cfg.Kraken.Streaming {
+		stream = kraken.NewStreamClient(cfg.Kraken.WebSocketURL, cfg.Pairs, provider)
+		stream.SetMaxAge(time.Duration(cfg.Kraken.StreamMaxAge) * time.Second)
+		defer stream.Stop()
+		provider = stream
+		logger.Info("Using Kraken WebSocket stream")
+	}
+
+	var elector *cluster.Elector
+	var fanOut *cluster.FanOut
+	if cfg.Cluster.Enabled {
+		// Cluster mode shares the cache's connection and only needs its own without a Redis cache.
+		if redisClient == nil {
+			client, err := cache.NewRedisClient(redisOptions(cfg.Redis))
+			if err != nil {
+				logger.Fatal("failed to connect cluster mode to Redis: %v", err)
+			}
+			defer client.Close()
+			redisClient = client
+		}
+		elector = cluster.NewElector(redisClient, cfg.Cluster.LockKey, time.Duration(cfg.Cluster.Lease)*time.Second)
+		fanOut = cluster.NewFanOut(redisClient, elector, cfg.Cluster.Channel)
+		// Followers ask the leader for Kraken prices they miss instead of calling Kraken themselves.
+		provider = fanOut.Relay(provider)
+	}
+
+	registry := application.NewProviderRegistry()
+	registry.Register(kraken.Source, provider)
+	for name, pc := range cfg.Providers {
+		if !pc.Enabled {
+			continue
+		}
+		p, ok := newExchangeProvider(name, pc)
+		if !ok {
+			logger.Warn("Unknown provider %s in configuration, skipping", name)
+			continue
+		}
+		registry.Register(name, p)
+		logger.Info("Registered provider %s", name)
+	}
+
+	defaultSource := kraken.Source
+	if cfg.Consensus.Enabled {
+		consensus, err := application.NewConsensusProvider(registry, cfg.Consensus.Sources, application.ConsensusOptions{
+			Method:       cfg.Consensus.Method,
+			MaxDeviation: decimal.NewFromFloat(cfg.Consensus.MaxDeviation),
+			MaxAge:       time.Duration(cfg.Consensus.MaxAge) * time.Second,
+			MinSources:   cfg.Consensus.MinSources,
+		})
+		if err != nil {
+			logger.Fatal("invalid consensus configuration: %v", err)
+		}
+		registry.Register(application.ConsensusSource, consensus)
+		if cfg.Consensus.Primary {
+			provider = consensus
+			defaultSource = application.ConsensusSource
+			logger.Info("Using consensus price from %v", cfg.Consensus.Sources)
+		}
+	}
 
-	service := application.NewLTPService(c, krakenClient, time.Duration(cfg.Cache.TTL)*time.Second)
+	if cfg.Failover.Enabled {
+		failover, err := application.NewFailoverProvider(registry, cfg.Failover.Sources, application.FailoverOptions{
+			MaxConsecutiveFailures: cfg.Failover.MaxFailures,
+			ProbeInterval:          time.Duration(cfg.Failover.ProbeInterval) * time.Second,
+			Window:                 cfg.Failover.Window,
+		})
+		if err != nil {
+			logger.Fatal("invalid failover configuration: %v", err)
+		}
+		registry.Register(application.FailoverSource, failover)
+		provider = failover
+		defaultSource = application.FailoverSource
+		logger.Info("Using failover across %v", cfg.Failover.Sources)
+	}
+
+	service := application.NewLTPService(c, provider, time.Duration(cfg.Cache.TTL)*time.Second)
+	service.SetProviders(registry, defaultSource)
+	if cfg.Cache.StaleWhileRevalidate {
+		service.SetStaleWhileRevalidate(time.Duration(cfg.Cache.MaxStaleness) * time.Second)
+	}
+	service.SetNegativeCache(application.NegativeCacheOptions{
+		UnsupportedTTL: time.Duration(cfg.Cache.Negative.UnsupportedTTL) * time.Second,
+		TransientTTL:   time.Duration(cfg.Cache.Negative.TransientTTL) * time.Second,
+	})
+	if cfg.Cache.ServeLastKnown {
+		service.SetLastKnownFallback(time.Duration(cfg.Cache.LastKnownMaxAge) * time.Second)
+	}
+	if stream != nil {
+		stream.SetSink(service)
+		stream.Start()
+	}
+	service.SetTickerProvider(krakenClient)
+	service.SetCandleProvider(krakenClient)
+	service.SetTradeProvider(krakenClient, application.TradesOptions{
+		TTL:          time.Duration(cfg.Trades.TTL) * time.Second,
+		Buffer:       cfg.Trades.Buffer,
+		DefaultLimit: cfg.Trades.DefaultLimit,
+		MaxLimit:     cfg.Trades.MaxLimit,
+	})
+	service.SetOrderBookProvider(krakenClient, application.OrderBookOptions{
+		TTL:          time.Duration(cfg.OrderBook.TTL) * time.Second,
+		DefaultDepth: cfg.OrderBook.DefaultDepth,
+		MaxDepth:     cfg.OrderBook.MaxDepth,
+	})
+
+	currencies := make(map[string]application.CurrencyFormat, len(cfg.Convert.Currencies))
+	for currency, cc := range cfg.Convert.Currencies {
+		currencies[currency] = application.CurrencyFormat{
+			Precision: int32(cc.Precision),
+			Rounding:  application.Rounding(cc.Rounding),
+		}
+	}
+	service.SetConversionOptions(application.ConversionOptions{
+		Default: application.CurrencyFormat{
+			Precision: int32(cfg.Convert.Precision),
+			Rounding:  application.Rounding(cfg.Convert.Rounding),
+		},
+		Currencies: currencies,
+	})
+
+	if cfg.CrossRate.Enabled {
+		// Legs are priced by the active provider, so the graph must be built from the markets it trades.
+		if catalog, ok := provider.(application.MarketCatalog); ok {
+			service.SetMarketCatalog(catalog, application.TriangulationOptions{
+				MaxLegs: cfg.CrossRate.MaxLegs,
+				Bridges: cfg.CrossRate.Bridges,
+			})
+		} else {
+			logger.Warn("Cross rates disabled: provider %s does not list its markets", defaultSource)
+		}
+	}
+
+	var historyStore *history.FileStore
+	if cfg.History.Enabled {
+		store, err := history.NewFileStore(cfg.History.Path, history.Options{
+			Retention:  time.Duration(cfg.History.Retention) * time.Hour,
+			MaxEntries: cfg.History.MaxEntries,
+		})
+		if err != nil {
+			logger.Fatal("failed to open price history: %v", err)
+		}
+		historyStore = store
+		service.SetHistoryStore(store)
+		if cfg.History.Path == "" {
+			logger.Info("Recording price history in memory")
+		} else {
+			logger.Info("Recording price history to %s", cfg.History.Path)
+		}
+	}
 
 	httpHandler := httpapi.NewHandler(service)
+	httpHandler.SetMaxSubscriptions(cfg.WebSocket.MaxSubscriptions)
+	httpHandler.SetSupportedPairs(cfg.Pairs)
 
 	refresherInterval := 30 * time.Second
 	ref := refresher.NewRefresher(service, cfg.Pairs, refresherInterval)
+
+	if cfg.Cluster.Enabled {
+		ref.SetLeader(elector)
+		elector.Start()
+		fanOut.Start(service)
+		logger.Info("Cluster mode enabled: refreshing only while holding %s", cfg.Cluster.LockKey)
+	}
+
 	ref.Start()
 	defer ref.Stop()
 
@@ -109,21 +307,71 @@ func main() {
 		}
 	}()
 
+	var grpcServer *grpcapi.Server
+	if cfg.GRPC.Enabled {
+		grpcAddr := ":" + strconv.Itoa(cfg.GRPC.Port)
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Fatal("failed to listen for gRPC on %s: %v", grpcAddr, err)
+		}
+		grpcServer = grpcapi.NewServer(service, cfg.Pairs)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.Info("starting gRPC server on %s", grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Fatal("gRPC server error: %v", err)
+			}
+		}()
+	}
+
 	<-ctx.Done()
 	logger.Info("shutdown signal received, initiating graceful shutdown...")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if err := httpHandler.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Streaming connections shutdown error: %v", err)
+	} else {
+		logger.Info("Streaming connections closed")
+	}
+
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("HTTP server shutdown error: %v", err)
 	} else {
 		logger.Info("HTTP server stopped gracefully")
 	}
 
+	if grpcServer != nil {
+		if err := grpcServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("gRPC server shutdown error: %v", err)
+		} else {
+			logger.Info("gRPC server stopped gracefully")
+		}
+	}
+
 	ref.Stop()
 	logger.Info("Refresher stopped")
 
+	if elector != nil {
+		fanOut.Stop()
+		elector.Stop()
+		logger.Info("Cluster coordination stopped")
+	}
+
+	if stream != nil {
+		stream.Stop()
+		logger.Info("Kraken stream stopped")
+	}
+
+	if historyStore != nil {
+		if err := historyStore.Close(); err != nil {
+			logger.Error("History close error: %v", err)
+		}
+	}
+
 	if closer, ok := c.(interface{ Close() error }); ok {
 		if err := closer.Close(); err != nil {
 			logger.Error("Cache close error: %v", err)
@@ -135,3 +383,66 @@ func main() {
 	wg.Wait()
 	logger.Info("All components stopped successfully")
 }
+
+// redisOptions applies the REDIS_* environment overrides to the configured deployment.
+func redisOptions(rc config.RedisConfig) cache.RedisOptions {
+	opts := cache.RedisOptions{
+		Mode:             rc.Mode,
+		Addrs:            rc.Addrs,
+		MasterName:       rc.MasterName,
+		Username:         rc.Username,
+		Password:         rc.Password,
+		SentinelUsername: rc.SentinelUsername,
+		SentinelPassword: rc.SentinelPassword,
+		DB:               rc.DB,
+		TLS: cache.RedisTLS{
+			Enabled:            rc.TLS.Enabled,
+			CAFile:             rc.TLS.CAFile,
+			CertFile:           rc.TLS.CertFile,
+			KeyFile:            rc.TLS.KeyFile,
+			ServerName:         rc.TLS.ServerName,
+			InsecureSkipVerify: rc.TLS.InsecureSkipVerify,
+		},
+		PoolSize:     rc.PoolSize,
+		MinIdleConns: rc.MinIdleConns,
+		PoolTimeout:  time.Duration(rc.PoolTimeout) * time.Second,
+	}
+	if addrs := os.Getenv("REDIS_ADDR"); addrs != "" {
+		opts.Addrs = strings.Split(addrs, ",")
+	}
+	if username := os.Getenv("REDIS_USERNAME"); username != "" {
+		opts.Username = username
+	}
+	if password := os.Getenv("REDIS_PASSWORD"); password != "" {
+		opts.Password = password
+	}
+	if username := os.Getenv("REDIS_SENTINEL_USERNAME"); username != "" {
+		opts.SentinelUsername = username
+	}
+	if password := os.Getenv("REDIS_SENTINEL_PASSWORD"); password != "" {
+		opts.SentinelPassword = password
+	}
+	if dbStr := os.Getenv("REDIS_DB"); dbStr != "" {
+		if db, err := strconv.Atoi(dbStr); err == nil {
+			opts.DB = db
+		}
+	}
+	return opts
+}
+
+func newExchangeProvider(name string, pc config.ProviderConfig) (application.MarketDataProvider, bool) {
+	timeout := uint(15)
+	if pc.Timeout > 0 {
+		timeout = uint(pc.Timeout)
+	}
+
+	switch name {
+	case coinbase.Source:
+		return coinbase.NewClient(pc.URL, timeout, pc.Symbols), true
+	case bitstamp.Source:
+		return bitstamp.NewClient(pc.URL, timeout, pc.Symbols), true
+	case binance.Source:
+		return binance.NewClient(pc.URL, timeout, pc.Symbols), true
+	}
+	return nil, false
+}
@@ -11,24 +11,211 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig  `yaml:"server"`
-	Pairs    []domain.Pair `yaml:"pairs"`
-	Cache    CacheConfig   `yaml:"cache"`
-	Kraken   KrakenConfig  `yaml:"kraken"`
-	LogLevel int           `yaml:"logLevel"`
-	LogPath  string        `yaml:"logOutput"`
+	Server    ServerConfig              `yaml:"server"`
+	Pairs     []domain.Pair             `yaml:"pairs"`
+	Cache     CacheConfig               `yaml:"cache"`
+	Kraken    KrakenConfig              `yaml:"kraken"`
+	Providers map[string]ProviderConfig `yaml:"providers"`
+	Consensus ConsensusConfig           `yaml:"consensus"`
+	Failover  FailoverConfig            `yaml:"failover"`
+	OrderBook OrderBookConfig           `yaml:"orderBook"`
+	Trades    TradesConfig              `yaml:"trades"`
+	History   HistoryConfig             `yaml:"history"`
+	WebSocket WebSocketConfig           `yaml:"websocket"`
+	GRPC      GRPCConfig                `yaml:"grpc"`
+	Convert   ConvertConfig             `yaml:"convert"`
+	CrossRate CrossRateConfig           `yaml:"crossRate"`
+	Cluster   ClusterConfig             `yaml:"cluster"`
+	Redis     RedisConfig               `yaml:"redis"`
+	LogLevel  int                       `yaml:"logLevel"`
+	LogPath   string                    `yaml:"logOutput"`
 }
 
 type ServerConfig struct {
 	Port int `yaml:"port"`
 }
 
+// CacheConfig times are in seconds. With StaleWhileRevalidate, prices older than TTL but younger than
+// MaxStaleness are served at once, flagged as stale, while they refresh in the background.
+// With ServeLastKnown, a failed fetch falls back to the last good price up to LastKnownMaxAge old.
 type CacheConfig struct {
-	TTL int `yaml:"ttl"`
+	TTL                  int                 `yaml:"ttl"`
+	StaleWhileRevalidate bool                `yaml:"staleWhileRevalidate"`
+	MaxStaleness         int                 `yaml:"maxStaleness"`
+	ServeLastKnown       bool                `yaml:"serveLastKnown"`
+	LastKnownMaxAge      int                 `yaml:"lastKnownMaxAge"`
+	L1                   L1CacheConfig       `yaml:"l1"`
+	Negative             NegativeCacheConfig `yaml:"negative"`
 }
 
+// L1CacheConfig sizes the in-process cache kept in front of Redis; TTL is in seconds.
+type L1CacheConfig struct {
+	Enabled    bool `yaml:"enabled"`
+	TTL        int  `yaml:"ttl"`
+	MaxEntries int  `yaml:"maxEntries"`
+}
+
+// NegativeCacheConfig sets how many seconds failed fetches are remembered, kept apart from good prices.
+// Zero stops caching that kind of failure.
+type NegativeCacheConfig struct {
+	UnsupportedTTL int `yaml:"unsupportedTtl"`
+	TransientTTL   int `yaml:"transientTtl"`
+}
+
+// KrakenConfig times are in seconds. StreamMaxAge is how old a streamed price may get before REST is asked instead.
 type KrakenConfig struct {
-	URL string `yaml:"url"`
+	URL          string          `yaml:"url"`
+	PairsRefresh int             `yaml:"pairsRefresh"`
+	Streaming    bool            `yaml:"streaming"`
+	WebSocketURL string          `yaml:"wsUrl"`
+	StreamMaxAge int             `yaml:"streamMaxAge"`
+	Breaker      BreakerConfig   `yaml:"breaker"`
+	RateLimit    RateLimitConfig `yaml:"rateLimit"`
+}
+
+// RateLimitConfig bounds requests to Kraken's public API; MaxWait is in milliseconds.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	Burst             int     `yaml:"burst"`
+	MaxWait           int     `yaml:"maxWait"`
+}
+
+// BreakerConfig guards upstream calls; Cooldown is in seconds.
+type BreakerConfig struct {
+	Enabled          bool `yaml:"enabled"`
+	FailureThreshold int  `yaml:"failureThreshold"`
+	Cooldown         int  `yaml:"cooldown"`
+}
+
+// ProviderConfig configures an additional exchange; Symbols overrides the adapter's default pair mapping.
+type ProviderConfig struct {
+	Enabled bool                   `yaml:"enabled"`
+	URL     string                 `yaml:"url"`
+	Timeout int                    `yaml:"timeout"`
+	Symbols map[domain.Pair]string `yaml:"symbols"`
+}
+
+// ConsensusConfig combines several providers into one price. MaxDeviation is a fraction (0.02 = 2%).
+type ConsensusConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Primary      bool     `yaml:"primary"`
+	Method       string   `yaml:"method"`
+	Sources      []string `yaml:"sources"`
+	MaxDeviation float64  `yaml:"maxDeviation"`
+	MaxAge       int      `yaml:"maxAge"`
+	MinSources   int      `yaml:"minSources"`
+}
+
+// FailoverConfig routes fetches to the healthiest of Sources. ProbeInterval is in seconds.
+type FailoverConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	Sources       []string `yaml:"sources"`
+	MaxFailures   int      `yaml:"maxFailures"`
+	ProbeInterval int      `yaml:"probeInterval"`
+	Window        int      `yaml:"window"`
+}
+
+// OrderBookConfig bounds the depth endpoint. TTL is in seconds and kept short since books move quickly.
+type OrderBookConfig struct {
+	TTL          int `yaml:"ttl"`
+	DefaultDepth int `yaml:"defaultDepth"`
+	MaxDepth     int `yaml:"maxDepth"`
+}
+
+// TradesConfig sizes the recent trades feed. TTL is how many seconds to serve the buffer before polling again.
+type TradesConfig struct {
+	TTL          int `yaml:"ttl"`
+	Buffer       int `yaml:"buffer"`
+	DefaultLimit int `yaml:"defaultLimit"`
+	MaxLimit     int `yaml:"maxLimit"`
+}
+
+// HistoryConfig controls the price history log. Retention is in hours; an empty Path keeps history in memory only.
+type HistoryConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Path       string `yaml:"path"`
+	Retention  int    `yaml:"retention"`
+	MaxEntries int    `yaml:"maxEntries"`
+}
+
+// WebSocketConfig limits the /ws API.
+type WebSocketConfig struct {
+	MaxSubscriptions int `yaml:"maxSubscriptions"`
+}
+
+// GRPCConfig controls the gRPC API served next to the REST handler.
+type GRPCConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+// ConvertConfig sets how /api/v1/convert rounds results; currencies without an entry use Precision and Rounding.
+type ConvertConfig struct {
+	Precision  int                       `yaml:"precision"`
+	Rounding   string                    `yaml:"rounding"`
+	Currencies map[string]CurrencyConfig `yaml:"currencies"`
+}
+
+// CurrencyConfig overrides the rounding of one currency; an empty Rounding keeps the default mode.
+type CurrencyConfig struct {
+	Precision int    `yaml:"precision"`
+	Rounding  string `yaml:"rounding"`
+}
+
+// CrossRateConfig controls triangulation of pairs Kraken does not list, through at most MaxLegs markets.
+// Bridges ranks intermediate currencies, most liquid first.
+type CrossRateConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	MaxLegs int      `yaml:"maxLegs"`
+	Bridges []string `yaml:"bridges"`
+}
+
+// ClusterConfig lets replicas share one refresher: the holder of LockKey polls Kraken and broadcasts
+// prices on Channel. Lease is in seconds; leadership moves to another replica once it expires.
+type ClusterConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	LockKey string `yaml:"lockKey"`
+	Lease   int    `yaml:"lease"`
+	Channel string `yaml:"channel"`
+}
+
+// RedisConfig describes the Redis deployment used when USE_REDIS is set. Mode is standalone, sentinel or
+// cluster, and Addrs lists the server, the sentinels or the cluster seed nodes. SentinelUsername and
+// SentinelPassword authenticate against the sentinels when they require it. PoolTimeout is in seconds;
+// zero pool settings keep the client defaults. REDIS_ADDR, REDIS_USERNAME, REDIS_PASSWORD,
+// REDIS_SENTINEL_USERNAME, REDIS_SENTINEL_PASSWORD and REDIS_DB override the file when set.
+type RedisConfig struct {
+	Mode             string         `yaml:"mode"`
+	Addrs            []string       `yaml:"addrs"`
+	MasterName       string         `yaml:"masterName"`
+	Username         string         `yaml:"username"`
+	Password         string         `yaml:"password"`
+	SentinelUsername string         `yaml:"sentinelUsername"`
+	SentinelPassword string         `yaml:"sentinelPassword"`
+	DB               int            `yaml:"db"`
+	TLS              RedisTLSConfig `yaml:"tls"`
+	PoolSize         int            `yaml:"poolSize"`
+	MinIdleConns     int            `yaml:"minIdleConns"`
+	PoolTimeout      int            `yaml:"poolTimeout"`
+}
+
+// RedisTLSConfig takes PEM file paths; without CAFile the system roots verify the server.
+type RedisTLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"caFile"`
+	CertFile           string `yaml:"certFile"`
+	KeyFile            string `yaml:"keyFile"`
+	ServerName         string `yaml:"serverName"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+}
+
+var redisModes = map[string]bool{"standalone": true, "sentinel": true, "cluster": true}
+
+var roundingModes = map[string]bool{"half_up": true, "half_even": true, "down": true, "up": true}
+
+// PairCatalog is implemented by providers that know which pairs they can serve.
+type PairCatalog interface {
+	IsSupported(pair domain.Pair) bool
 }
 
 var (
@@ -125,10 +312,99 @@ func Default() Config {
 		},
 		Pairs: []domain.Pair{"BTC/USD", "BTC/EUR", "BTC/CHF"},
 		Cache: CacheConfig{
-			TTL: 60,
+			TTL:                  60,
+			StaleWhileRevalidate: false,
+			MaxStaleness:         300,
+			ServeLastKnown:       false,
+			LastKnownMaxAge:      3600,
+			L1: L1CacheConfig{
+				Enabled:    false,
+				TTL:        1,
+				MaxEntries: 10000,
+			},
+			Negative: NegativeCacheConfig{
+				UnsupportedTTL: 300,
+				TransientTTL:   5,
+			},
 		},
 		Kraken: KrakenConfig{
-			URL: "https://api.kraken.com",
+			URL:          "https://api.kraken.com",
+			PairsRefresh: 3600,
+			Streaming:    false,
+			WebSocketURL: "wss://ws.kraken.com/v2",
+			StreamMaxAge: 60,
+			Breaker: BreakerConfig{
+				Enabled:          false,
+				FailureThreshold: 5,
+				Cooldown:         30,
+			},
+			RateLimit: RateLimitConfig{
+				RequestsPerSecond: 1,
+				Burst:             5,
+				MaxWait:           2000,
+			},
+		},
+		Consensus: ConsensusConfig{
+			Enabled:      false,
+			Method:       "median",
+			MaxDeviation: 0.02,
+			MaxAge:       60,
+			MinSources:   1,
+		},
+		Failover: FailoverConfig{
+			Enabled:       false,
+			MaxFailures:   3,
+			ProbeInterval: 30,
+			Window:        20,
+		},
+		OrderBook: OrderBookConfig{
+			TTL:          2,
+			DefaultDepth: 10,
+			MaxDepth:     100,
+		},
+		Trades: TradesConfig{
+			TTL:          5,
+			Buffer:       1000,
+			DefaultLimit: 50,
+			MaxLimit:     500,
+		},
+		History: HistoryConfig{
+			Enabled:    false,
+			Path:       "",
+			Retention:  168,
+			MaxEntries: 100000,
+		},
+		WebSocket: WebSocketConfig{
+			MaxSubscriptions: 20,
+		},
+		GRPC: GRPCConfig{
+			Enabled: false,
+			Port:    9090,
+		},
+		Convert: ConvertConfig{
+			Precision: 8,
+			Rounding:  "half_even",
+			Currencies: map[string]CurrencyConfig{
+				"USD": {Precision: 2},
+				"EUR": {Precision: 2},
+				"CHF": {Precision: 2},
+				"BTC": {Precision: 8},
+			},
+		},
+		CrossRate: CrossRateConfig{
+			Enabled: false,
+			MaxLegs: 3,
+			Bridges: []string{"USD", "EUR", "BTC", "USDT", "ETH"},
+		},
+		Cluster: ClusterConfig{
+			Enabled: false,
+			LockKey: "ltp-service:refresher:leader",
+			Lease:   15,
+			Channel: "ltp-service:prices",
+		},
+		Redis: RedisConfig{
+			Mode:  "standalone",
+			Addrs: []string{"localhost:6379"},
 		},
 		LogLevel: 0,
 		LogPath:  "/tmp/app.log",
@@ -160,7 +436,7 @@ func Load(path string) Config {
 	return config
 }
 
-func (c Config) Validate() {
+func (c Config) Validate(catalogs ...PairCatalog) {
 	logger := log.GetInstance()
 	
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
@@ -175,6 +451,30 @@ func (c Config) Validate() {
 		panic(errMsg)
 	}
 
+	if c.Cache.L1.Enabled && (c.Cache.L1.TTL <= 0 || c.Cache.L1.MaxEntries <= 0) {
+		errMsg := "Cache l1 ttl and maxEntries must be positive"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Cache.StaleWhileRevalidate && c.Cache.MaxStaleness <= c.Cache.TTL {
+		errMsg := fmt.Sprintf("Cache maxStaleness (%d) must be greater than ttl (%d)", c.Cache.MaxStaleness, c.Cache.TTL)
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Cache.Negative.UnsupportedTTL < 0 || c.Cache.Negative.TransientTTL < 0 {
+		errMsg := "Cache negative TTLs must not be negative"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Cache.ServeLastKnown && c.Cache.LastKnownMaxAge <= c.Cache.TTL {
+		errMsg := fmt.Sprintf("Cache lastKnownMaxAge (%d) must be greater than ttl (%d)", c.Cache.LastKnownMaxAge, c.Cache.TTL)
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
 	if len(c.Pairs) == 0 {
 		errMsg := "Must specify at least one trading pair"
 		logger.Error(errMsg)
@@ -186,4 +486,181 @@ func (c Config) Validate() {
 		logger.Warn(errMsg)
 		panic(errMsg)	
 	}
+
+	if c.Kraken.Breaker.Enabled && (c.Kraken.Breaker.FailureThreshold <= 0 || c.Kraken.Breaker.Cooldown <= 0) {
+		errMsg := "Kraken breaker failureThreshold and cooldown must be positive"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Kraken.RateLimit.RequestsPerSecond < 0 || c.Kraken.RateLimit.Burst < 0 || c.Kraken.RateLimit.MaxWait < 0 {
+		errMsg := "Kraken rate limit settings must not be negative"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Kraken.Streaming && c.Kraken.WebSocketURL == "" {
+		errMsg := "Kraken WebSocket URL is required when streaming is enabled"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Kraken.Streaming && c.Kraken.StreamMaxAge <= 0 {
+		errMsg := "Kraken streamMaxAge must be positive when streaming is enabled"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	for name, provider := range c.Providers {
+		if !provider.Enabled {
+			continue
+		}
+		if provider.URL == "" {
+			errMsg := fmt.Sprintf("Provider %s requires a url", name)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if provider.Timeout < 0 {
+			errMsg := fmt.Sprintf("Provider %s has an invalid timeout: %d", name, provider.Timeout)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if c.Consensus.Enabled {
+		if c.Consensus.Method != "median" && c.Consensus.Method != "vwap" {
+			errMsg := fmt.Sprintf("Invalid consensus method: %s", c.Consensus.Method)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if len(c.Consensus.Sources) == 0 {
+			errMsg := "Consensus requires at least one source"
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if c.Consensus.MaxDeviation < 0 || c.Consensus.MinSources > len(c.Consensus.Sources) {
+			errMsg := fmt.Sprintf("Invalid consensus thresholds: maxDeviation=%v minSources=%d", c.Consensus.MaxDeviation, c.Consensus.MinSources)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if c.Failover.Enabled {
+		if len(c.Failover.Sources) == 0 {
+			errMsg := "Failover requires at least one source"
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if c.Consensus.Enabled && c.Consensus.Primary {
+			errMsg := "Consensus cannot be primary while failover is enabled; list it as a failover source instead"
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if c.OrderBook.TTL <= 0 || c.OrderBook.DefaultDepth <= 0 || c.OrderBook.MaxDepth > 500 ||
+		c.OrderBook.DefaultDepth > c.OrderBook.MaxDepth {
+		errMsg := "Order book requires a positive TTL and 0 < defaultDepth <= maxDepth <= 500"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Trades.TTL <= 0 || c.Trades.DefaultLimit <= 0 || c.Trades.DefaultLimit > c.Trades.MaxLimit ||
+		c.Trades.MaxLimit > c.Trades.Buffer {
+		errMsg := "Trades require a positive TTL and 0 < defaultLimit <= maxLimit <= buffer"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.History.Retention < 0 || c.History.MaxEntries < 0 {
+		errMsg := "History retention and maxEntries must not be negative"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.WebSocket.MaxSubscriptions <= 0 {
+		errMsg := "WebSocket maxSubscriptions must be positive"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.GRPC.Enabled {
+		if c.GRPC.Port <= 0 || c.GRPC.Port > 65535 {
+			errMsg := fmt.Sprintf("gRPC invalid Port: %d", c.GRPC.Port)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if c.GRPC.Port == c.Server.Port {
+			errMsg := fmt.Sprintf("gRPC port %d is already used by the HTTP server", c.GRPC.Port)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if c.Convert.Precision < 0 || !roundingModes[c.Convert.Rounding] {
+		errMsg := fmt.Sprintf("Convert requires a non-negative precision and a rounding of half_up, half_even, down or up, got %d/%q",
+			c.Convert.Precision, c.Convert.Rounding)
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+	for currency, cc := range c.Convert.Currencies {
+		if cc.Precision < 0 || (cc.Rounding != "" && !roundingModes[cc.Rounding]) {
+			errMsg := fmt.Sprintf("Invalid convert settings for %s", currency)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if c.CrossRate.Enabled && (c.CrossRate.MaxLegs < 2 || c.CrossRate.MaxLegs > 4) {
+		errMsg := fmt.Sprintf("CrossRate maxLegs must be between 2 and 4, got %d", c.CrossRate.MaxLegs)
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Cluster.Enabled {
+		if c.Cluster.LockKey == "" || c.Cluster.Channel == "" {
+			errMsg := "Cluster lockKey and channel must be set"
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+		if c.Cluster.Lease < 3 {
+			errMsg := fmt.Sprintf("Cluster lease must be at least 3 seconds, got %d", c.Cluster.Lease)
+			logger.Error(errMsg)
+			panic(errMsg)
+		}
+	}
+
+	if !redisModes[c.Redis.Mode] {
+		errMsg := fmt.Sprintf("Redis mode must be standalone, sentinel or cluster, got %q", c.Redis.Mode)
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Redis.Mode == "sentinel" && c.Redis.MasterName == "" {
+		errMsg := "Redis sentinel mode requires masterName"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if c.Redis.PoolSize < 0 || c.Redis.MinIdleConns < 0 || c.Redis.PoolTimeout < 0 {
+		errMsg := "Redis pool settings must not be negative"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	if (c.Redis.TLS.CertFile == "") != (c.Redis.TLS.KeyFile == "") {
+		errMsg := "Redis TLS certFile and keyFile must be set together"
+		logger.Error(errMsg)
+		panic(errMsg)
+	}
+
+	for _, catalog := range catalogs {
+		for _, pair := range c.Pairs {
+			if !catalog.IsSupported(pair) {
+				errMsg := fmt.Sprintf("Unsupported trading pair: %s", pair)
+				logger.Error(errMsg)
+				panic(errMsg)
+			}
+		}
+	}
 }
@@ -0,0 +1,65 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/exchange"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const Source = "binance"
+
+// Binance answers -1121 for symbols it does not list.
+const invalidSymbolCode = -1121
+
+type Client struct {
+	baseURL string
+	rest    *exchange.REST
+	symbols map[domain.Pair]string
+}
+
+// NewClient builds a Binance public REST client; symbols overrides the default BTC/USDT -> BTCUSDT mapping.
+func NewClient(baseURL string, timeout uint, symbols map[domain.Pair]string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		rest:    exchange.NewREST(Source, "Binance", timeout, unlisted),
+		symbols: symbols,
+	}
+}
+
+type tickerResp struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+type errorResp struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+func unlisted(status int, body []byte) bool {
+	var parsed errorResp
+	return status == http.StatusBadRequest && json.Unmarshal(body, &parsed) == nil && parsed.Code == invalidSymbolCode
+}
+
+// Fetch reads the price ticker, which carries no volume or trade time.
+func (c *Client) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	var parsed tickerResp
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", c.baseURL, c.symbol(pair))
+	if ltp, ok := c.rest.GetJSON(ctx, pair, url, &parsed); !ok {
+		return ltp
+	}
+	return c.rest.PriceLTP(pair, parsed.Price, "", time.Now())
+}
+
+func (c *Client) symbol(pair domain.Pair) string {
+	if symbol, ok := c.symbols[pair]; ok {
+		return symbol
+	}
+	return strings.ToUpper(strings.ReplaceAll(string(pair), "/", ""))
+}
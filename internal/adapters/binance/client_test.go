@@ -0,0 +1,81 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, ltp domain.LTP)
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"symbol":"BTCUSDT","price":"65000.12000000"}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Empty(t, ltp.Error)
+				assert.Equal(t, "65000.12", ltp.Amount.String())
+				assert.Nil(t, ltp.Volume)
+				assert.Equal(t, Source, ltp.Source)
+			},
+		},
+		{
+			name:   "invalid symbol is unsupported",
+			status: http.StatusBadRequest,
+			body:   `{"code":-1121,"msg":"Invalid symbol."}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsUnsupported(), ltp.Error)
+			},
+		},
+		{
+			name:   "other bad request is not unsupported",
+			status: http.StatusBadRequest,
+			body:   `{"code":-1100,"msg":"Illegal characters found in parameter."}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.False(t, ltp.IsUnsupported())
+				assert.Contains(t, ltp.Error, "status 400")
+			},
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   `{"code":-1003,"msg":"Too many requests."}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsRateLimited(), ltp.Error)
+			},
+		},
+		{
+			name:   "malformed body",
+			status: http.StatusOK,
+			body:   `{"symbol":"BTCUSDT","price":65000}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Contains(t, ltp.Error, "invalid Binance response")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v3/ticker/price", r.URL.Path)
+				assert.Equal(t, "BTCUSDT", r.URL.Query().Get("symbol"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			ltp := NewClient(server.URL, 5, nil).Fetch(context.Background(), "BTC/USDT")
+			assert.Equal(t, domain.Pair("BTC/USDT"), ltp.Pair)
+			tt.check(t, ltp)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package bitstamp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/exchange"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const Source = "bitstamp"
+
+type Client struct {
+	baseURL string
+	rest    *exchange.REST
+	symbols map[domain.Pair]string
+}
+
+// NewClient builds a Bitstamp client; symbols overrides the default BTC/USD -> btcusd mapping.
+func NewClient(baseURL string, timeout uint, symbols map[domain.Pair]string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		rest:    exchange.NewREST(Source, "Bitstamp", timeout, nil),
+		symbols: symbols,
+	}
+}
+
+type tickerResp struct {
+	Last      string `json:"last"`
+	Volume    string `json:"volume"`
+	Timestamp string `json:"timestamp"`
+}
+
+func (c *Client) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	var parsed tickerResp
+	url := fmt.Sprintf("%s/api/v2/ticker/%s/", c.baseURL, c.marketSymbol(pair))
+	if ltp, ok := c.rest.GetJSON(ctx, pair, url, &parsed); !ok {
+		return ltp
+	}
+
+	var ts time.Time
+	if secs, err := strconv.ParseInt(parsed.Timestamp, 10, 64); err == nil {
+		ts = time.Unix(secs, 0)
+	}
+	return c.rest.PriceLTP(pair, parsed.Last, parsed.Volume, ts)
+}
+
+func (c *Client) marketSymbol(pair domain.Pair) string {
+	if symbol, ok := c.symbols[pair]; ok {
+		return symbol
+	}
+	return strings.ToLower(strings.ReplaceAll(string(pair), "/", ""))
+}
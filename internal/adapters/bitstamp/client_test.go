@@ -0,0 +1,72 @@
+package bitstamp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, ltp domain.LTP)
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"last":"65000.12","volume":"42.1","timestamp":"1735787045"}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Empty(t, ltp.Error)
+				assert.Equal(t, "65000.12", ltp.Amount.String())
+				assert.Equal(t, "42.1", ltp.Volume.String())
+				assert.Equal(t, int64(1735787045), ltp.Timestamp.Unix())
+				assert.Equal(t, Source, ltp.Source)
+			},
+		},
+		{
+			name:   "not found is unsupported",
+			status: http.StatusNotFound,
+			body:   `<html>Not Found</html>`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsUnsupported(), ltp.Error)
+			},
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   ``,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsRateLimited(), ltp.Error)
+			},
+		},
+		{
+			name:   "malformed body",
+			status: http.StatusOK,
+			body:   `not json`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Contains(t, ltp.Error, "invalid Bitstamp response")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/api/v2/ticker/btcusd/", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			ltp := NewClient(server.URL, 5, nil).Fetch(context.Background(), "BTC/USD")
+			assert.Equal(t, domain.Pair("BTC/USD"), ltp.Pair)
+			tt.check(t, ltp)
+		})
+	}
+}
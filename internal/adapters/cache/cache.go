@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"context"
+	"strconv"
 	"sync"
 	"time"
 
@@ -13,22 +15,38 @@ type cacheEntry struct {
 	expiresAt time.Time
 }
 
+// snapshotEntry holds market data other than LTPs (tickers, order books) under a prefixed key.
+type snapshotEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
 type InMemoryCache struct {
-	data map[domain.Pair]cacheEntry
+	data    map[domain.Pair]cacheEntry
+	snapshots map[string]snapshotEntry
 	mu   sync.RWMutex
 	ttl  time.Duration
 	lastValues map[domain.Pair]domain.LTP
+	maxEntries int
 }
 
 func NewInMemoryCache(ttl time.Duration) *InMemoryCache {
 	return &InMemoryCache{
 		data:       make(map[domain.Pair]cacheEntry),
+		snapshots:  make(map[string]snapshotEntry),
 		ttl:        ttl,
 		lastValues: make(map[domain.Pair]domain.LTP),
 	}
 }
 
-func (c *InMemoryCache) Get(pair domain.Pair) (ltp domain.LTP, found bool) {
+// SetMaxEntries bounds how many prices and snapshots are kept; when full, the entry closest to expiry is evicted.
+func (c *InMemoryCache) SetMaxEntries(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxEntries = n
+}
+
+func (c *InMemoryCache) Get(ctx context.Context, pair domain.Pair) (ltp domain.LTP, found bool) {
 	// Mecanismo de recuperación para Get
 	defer func() {
 		if rec := recover(); rec != nil {
@@ -59,10 +77,13 @@ func (c *InMemoryCache) Get(pair domain.Pair) (ltp domain.LTP, found bool) {
 	return entry.ltp, true
 }
 
-func (c *InMemoryCache) Set(pair domain.Pair, ltp domain.LTP) {
+func (c *InMemoryCache) Set(ctx context.Context, pair domain.Pair, ltp domain.LTP) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			log.GetInstance().Debug("Recovered from panic in InMemoryCache Set: %v", rec)
+			if !isGood(ltp) {
+				return
+			}
 			c.mu.Lock()
 			defer c.mu.Unlock()
 			c.lastValues[pair] = ltp
@@ -81,11 +102,134 @@ func (c *InMemoryCache) Set(pair domain.Pair, ltp domain.LTP) {
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if _, exists := c.data[pair]; !exists && c.maxEntries > 0 && len(c.data) >= c.maxEntries {
+		c.evictPrice()
+	}
 	c.data[pair] = cacheEntry{
 		ltp:       ltp,
 		expiresAt: exp,
 	}
-	c.lastValues[pair] = ltp
+	if isGood(ltp) {
+		c.lastValues[pair] = ltp
+	}
+}
+
+// GetLastKnown returns the last good price stored for pair, even after its entry expired or was evicted.
+func (c *InMemoryCache) GetLastKnown(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ltp, ok := c.lastValues[pair]
+	return ltp, ok
+}
+
+// isGood reports whether ltp may serve as a last known price: failed fetches never replace one.
+func isGood(ltp domain.LTP) bool {
+	return ltp.Error == "" && ltp.Amount.IsPositive()
+}
+
+// Delete drops the cached price so the next Get misses.
+func (c *InMemoryCache) Delete(ctx context.Context, pair domain.Pair) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, pair)
+}
+
+func (c *InMemoryCache) evictPrice() {
+	var victim domain.Pair
+	var earliest time.Time
+	for pair, entry := range c.data {
+		if victim == "" || entry.expiresAt.Before(earliest) {
+			victim, earliest = pair, entry.expiresAt
+		}
+	}
+	delete(c.data, victim)
+}
+
+func (c *InMemoryCache) GetTicker(ctx context.Context, pair domain.Pair) (domain.Ticker, bool) {
+	ticker, ok := c.getSnapshot(tickerKey(pair)).(domain.Ticker)
+	return ticker, ok
+}
+
+func (c *InMemoryCache) SetTicker(ctx context.Context, pair domain.Pair, ticker domain.Ticker) {
+	if ticker.Timestamp.IsZero() {
+		ticker.Timestamp = time.Now()
+	}
+	c.setSnapshot(tickerKey(pair), ticker)
+}
+
+func (c *InMemoryCache) GetOrderBook(ctx context.Context, pair domain.Pair) (domain.OrderBook, bool) {
+	book, ok := c.getSnapshot(orderBookKey(pair)).(domain.OrderBook)
+	return book, ok
+}
+
+func (c *InMemoryCache) SetOrderBook(ctx context.Context, pair domain.Pair, book domain.OrderBook) {
+	c.setSnapshot(orderBookKey(pair), book)
+}
+
+func (c *InMemoryCache) GetCandles(ctx context.Context, pair domain.Pair, interval int) (domain.CandleSeries, bool) {
+	series, ok := c.getSnapshot(candlesKey(pair, interval)).(domain.CandleSeries)
+	return series, ok
+}
+
+func (c *InMemoryCache) SetCandles(ctx context.Context, series domain.CandleSeries) {
+	c.setSnapshot(candlesKey(series.Pair, series.Interval), series)
+}
+
+func (c *InMemoryCache) getSnapshot(key string) interface{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.snapshots[key]
+	if !ok || (c.ttl > 0 && time.Now().After(entry.expiresAt)) {
+		return nil
+	}
+	return entry.value
+}
+
+func (c *InMemoryCache) setSnapshot(key string, value interface{}) {
+	exp := time.Time{}
+	if c.ttl > 0 {
+		exp = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.snapshots[key]; !exists && c.maxEntries > 0 && len(c.snapshots) >= c.maxEntries {
+		c.evictSnapshot()
+	}
+	c.snapshots[key] = snapshotEntry{
+		value:     value,
+		expiresAt: exp,
+	}
+}
+
+func (c *InMemoryCache) deleteSnapshot(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.snapshots, key)
+}
+
+func (c *InMemoryCache) evictSnapshot() {
+	var victim string
+	var earliest time.Time
+	for key, entry := range c.snapshots {
+		if victim == "" || entry.expiresAt.Before(earliest) {
+			victim, earliest = key, entry.expiresAt
+		}
+	}
+	delete(c.snapshots, victim)
+}
+
+func tickerKey(pair domain.Pair) string {
+	return "ticker:" + string(pair)
+}
+
+func orderBookKey(pair domain.Pair) string {
+	return "orderbook:" + string(pair)
+}
+
+func candlesKey(pair domain.Pair, interval int) string {
+	return "ohlc:" + string(pair) + ":" + strconv.Itoa(interval)
 }
 
 func (c *InMemoryCache) CheckConnectivity() bool {
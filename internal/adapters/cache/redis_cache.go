@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
@@ -11,9 +12,10 @@ import (
 )
 
 type RedisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 	ttl    time.Duration
 	lastValues map[domain.Pair]domain.LTP
+	lastMu sync.RWMutex
 }
 
 func NewRedisCache(addr, password string, db int, ttl time.Duration) *RedisCache {
@@ -23,14 +25,24 @@ func NewRedisCache(addr, password string, db int, ttl time.Duration) *RedisCache
 		DB:       db,
 	})
 
+	return NewRedisCacheWithClient(rdb, ttl)
+}
+
+// NewRedisCacheWithClient caches through any client from NewRedisClient: standalone, Sentinel or Cluster.
+func NewRedisCacheWithClient(client redis.UniversalClient, ttl time.Duration) *RedisCache {
 	return &RedisCache{
-		client:     rdb,
+		client:     client,
 		ttl:        ttl,
 		lastValues: make(map[domain.Pair]domain.LTP),
 	}
 }
 
-func (r *RedisCache) Get(pair domain.Pair) (ltp domain.LTP, found bool) {
+// Close releases the client's connections.
+func (r *RedisCache) Close() error {
+	return r.client.Close()
+}
+
+func (r *RedisCache) Get(ctx context.Context, pair domain.Pair) (ltp domain.LTP, found bool) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			log.GetInstance().Debug("Recovered from panic in Get: %v", rec)
@@ -39,7 +51,6 @@ func (r *RedisCache) Get(pair domain.Pair) (ltp domain.LTP, found bool) {
 		}
 	}()
 
-	ctx := context.Background()
 	val, err := r.client.Get(ctx, string(pair)).Result()
 	if err == redis.Nil {
 		return domain.LTP{}, false
@@ -54,16 +65,16 @@ func (r *RedisCache) Get(pair domain.Pair) (ltp domain.LTP, found bool) {
 	return ltp, true
 }
 
-func (r *RedisCache) Set(pair domain.Pair, ltp domain.LTP) {
+func (r *RedisCache) Set(ctx context.Context, pair domain.Pair, ltp domain.LTP) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			log.GetInstance().Debug("Recovered from panic in Set: %v", rec)
-			r.lastValues[pair] = ltp
-			log.GetInstance().Debug("Stored last value for %s as fallback", pair)
+			if r.remember(pair, ltp) {
+				log.GetInstance().Debug("Stored last value for %s as fallback", pair)
+			}
 		}
 	}()
 
-	ctx := context.Background()
 	if ltp.Timestamp.IsZero() {
 		ltp.Timestamp = time.Now()
 	}
@@ -76,7 +87,85 @@ func (r *RedisCache) Set(pair domain.Pair, ltp domain.LTP) {
 		panic("Redis set error: " + err.Error())
 	}
 
+	r.remember(pair, ltp)
+}
+
+// remember keeps ltp in process as pair's last known price if it is a good one.
+func (r *RedisCache) remember(pair domain.Pair, ltp domain.LTP) bool {
+	if !isGood(ltp) {
+		return false
+	}
+	r.lastMu.Lock()
+	defer r.lastMu.Unlock()
 	r.lastValues[pair] = ltp
+	return true
+}
+
+// GetLastKnown returns the last good price this process stored for pair, even while Redis is unreachable.
+func (r *RedisCache) GetLastKnown(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	r.lastMu.RLock()
+	defer r.lastMu.RUnlock()
+	ltp, ok := r.lastValues[pair]
+	return ltp, ok
+}
+
+func (r *RedisCache) GetTicker(ctx context.Context, pair domain.Pair) (domain.Ticker, bool) {
+	var ticker domain.Ticker
+	ok := r.getJSON(ctx, tickerKey(pair), &ticker)
+	return ticker, ok
+}
+
+func (r *RedisCache) SetTicker(ctx context.Context, pair domain.Pair, ticker domain.Ticker) {
+	if ticker.Timestamp.IsZero() {
+		ticker.Timestamp = time.Now()
+	}
+	r.setJSON(ctx, tickerKey(pair), ticker)
+}
+
+func (r *RedisCache) GetOrderBook(ctx context.Context, pair domain.Pair) (domain.OrderBook, bool) {
+	var book domain.OrderBook
+	ok := r.getJSON(ctx, orderBookKey(pair), &book)
+	return book, ok
+}
+
+func (r *RedisCache) SetOrderBook(ctx context.Context, pair domain.Pair, book domain.OrderBook) {
+	r.setJSON(ctx, orderBookKey(pair), book)
+}
+
+func (r *RedisCache) GetCandles(ctx context.Context, pair domain.Pair, interval int) (domain.CandleSeries, bool) {
+	var series domain.CandleSeries
+	ok := r.getJSON(ctx, candlesKey(pair, interval), &series)
+	return series, ok
+}
+
+func (r *RedisCache) SetCandles(ctx context.Context, series domain.CandleSeries) {
+	r.setJSON(ctx, candlesKey(series.Pair, series.Interval), series)
+}
+
+func (r *RedisCache) getJSON(ctx context.Context, key string, out interface{}) bool {
+	val, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.GetInstance().Debug("Redis get error for %s: %v", key, err)
+		}
+		return false
+	}
+	if err := json.Unmarshal([]byte(val), out); err != nil {
+		log.GetInstance().Debug("JSON unmarshal error for %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+func (r *RedisCache) setJSON(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.GetInstance().Debug("JSON marshal error for %s: %v", key, err)
+		return
+	}
+	if err := r.client.Set(ctx, key, data, r.ttl).Err(); err != nil {
+		log.GetInstance().Debug("Redis set error for %s: %v", key, err)
+	}
 }
 
 func (r *RedisCache) CheckConnectivity() bool {
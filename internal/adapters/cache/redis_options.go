@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Deployments NewRedisClient can connect to.
+const (
+	RedisStandalone = "standalone"
+	RedisSentinel   = "sentinel"
+	RedisCluster    = "cluster"
+)
+
+// RedisTLS points at PEM files. Without a CAFile the system roots verify the server.
+type RedisTLS struct {
+	Enabled            bool
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// RedisOptions describes a standalone server, a Sentinel-managed master or a Cluster. Addrs lists the
+// server, the sentinels or the cluster seed nodes respectively; Cluster ignores DB. Username and Password
+// authenticate against the data nodes, SentinelUsername and SentinelPassword against the sentinels.
+type RedisOptions struct {
+	Mode             string
+	Addrs            []string
+	MasterName       string
+	Username         string
+	Password         string
+	SentinelUsername string
+	SentinelPassword string
+	DB               int
+	TLS              RedisTLS
+	PoolSize         int
+	MinIdleConns     int
+	PoolTimeout      time.Duration
+}
+
+func NewRedisClient(o RedisOptions) (redis.UniversalClient, error) {
+	if len(o.Addrs) == 0 {
+		return nil, fmt.Errorf("no Redis addresses configured")
+	}
+	opts, err := o.universal()
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Mode {
+	case "", RedisStandalone:
+		return redis.NewClient(opts.Simple()), nil
+	case RedisSentinel:
+		if o.MasterName == "" {
+			return nil, fmt.Errorf("sentinel mode requires a master name")
+		}
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case RedisCluster:
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("unknown Redis mode %q", o.Mode)
+	}
+}
+
+func (o RedisOptions) universal() (*redis.UniversalOptions, error) {
+	opts := &redis.UniversalOptions{
+		Addrs:            o.Addrs,
+		MasterName:       o.MasterName,
+		Username:         o.Username,
+		Password:         o.Password,
+		SentinelUsername: o.SentinelUsername,
+		SentinelPassword: o.SentinelPassword,
+		DB:               o.DB,
+		PoolSize:         o.PoolSize,
+		MinIdleConns:     o.MinIdleConns,
+		PoolTimeout:      o.PoolTimeout,
+	}
+	if o.TLS.Enabled {
+		tlsConfig, err := o.TLS.config()
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsConfig
+	}
+	return opts, nil
+}
+
+func (t RedisTLS) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading Redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in Redis CA file %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading Redis client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRedisClient_Modes(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireUserAuth("ltp", "secret")
+	ctx := context.Background()
+
+	for _, mode := range []string{RedisStandalone, RedisCluster} {
+		t.Run(mode, func(t *testing.T) {
+			client, err := NewRedisClient(RedisOptions{
+				Mode:     mode,
+				Addrs:    []string{server.Addr()},
+				Username: "ltp",
+				Password: "secret",
+				PoolSize: 2,
+			})
+			require.NoError(t, err)
+			c := NewRedisCacheWithClient(client, time.Minute)
+
+			c.Set(ctx, "BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now().UTC()})
+			got, ok := c.Get(ctx, "BTC/USD")
+			require.True(t, ok)
+			assert.Equal(t, "50000", got.Amount.String())
+
+			require.NoError(t, c.Close())
+			assert.ErrorIs(t, client.Ping(ctx).Err(), redis.ErrClosed)
+		})
+	}
+}
+
+func TestRedisOptions_SentinelAuth(t *testing.T) {
+	opts, err := RedisOptions{
+		Mode:             RedisSentinel,
+		Addrs:            []string{"localhost:26379"},
+		MasterName:       "mymaster",
+		Username:         "ltp",
+		Password:         "secret",
+		SentinelUsername: "watcher",
+		SentinelPassword: "sentinel-secret",
+	}.universal()
+	require.NoError(t, err)
+
+	failover := opts.Failover()
+	assert.Equal(t, "ltp", failover.Username)
+	assert.Equal(t, "secret", failover.Password)
+	assert.Equal(t, "watcher", failover.SentinelUsername)
+	assert.Equal(t, "sentinel-secret", failover.SentinelPassword)
+}
+
+func TestNewRedisClient_RejectsInvalidOptions(t *testing.T) {
+	_, err := NewRedisClient(RedisOptions{Mode: RedisStandalone})
+	assert.Error(t, err)
+
+	_, err = NewRedisClient(RedisOptions{Mode: RedisSentinel, Addrs: []string{"localhost:26379"}})
+	assert.ErrorContains(t, err, "master name")
+
+	_, err = NewRedisClient(RedisOptions{Mode: "replicated", Addrs: []string{"localhost:6379"}})
+	assert.ErrorContains(t, err, "unknown Redis mode")
+
+	_, err = NewRedisClient(RedisOptions{Addrs: []string{"localhost:6379"}, TLS: RedisTLS{Enabled: true, CAFile: "/nonexistent/ca.pem"}})
+	assert.ErrorContains(t, err, "CA file")
+}
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// Replicas announce writes on this channel so the others drop their L1 copy.
+const invalidationChannel = "ltp-service:cache:invalidate"
+
+// Prices are announced under this prefix; snapshots under their own cache keys.
+const priceKeyPrefix = "ltp:"
+
+var tierLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "cache_tier_lookups_total",
+	Help: "Cache lookups per tier (l1, l2) and result (hit, miss)",
+}, []string{"tier", "result"})
+
+type invalidation struct {
+	Origin string `json:"origin"`
+	Key    string `json:"key"`
+}
+
+// TieredCache reads through a short-lived in-process L1 to Redis (L2) and writes through to both.
+type TieredCache struct {
+	l1     *InMemoryCache
+	l2     *RedisCache
+	origin string
+	pubsub *redis.PubSub
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func NewTieredCache(l1 *InMemoryCache, l2 *RedisCache) *TieredCache {
+	t := &TieredCache{
+		l1:     l1,
+		l2:     l2,
+		origin: newOrigin(),
+		pubsub: l2.client.Subscribe(context.Background(), invalidationChannel),
+		done:   make(chan struct{}),
+	}
+	go t.listen()
+	return t
+}
+
+func newOrigin() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (t *TieredCache) listen() {
+	defer close(t.done)
+	for msg := range t.pubsub.Channel() {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			log.GetInstance().Debug("Ignoring malformed cache invalidation %q: %v", msg.Payload, err)
+			continue
+		}
+		if inv.Origin == t.origin {
+			continue
+		}
+		if pair, ok := strings.CutPrefix(inv.Key, priceKeyPrefix); ok {
+			t.l1.Delete(context.Background(), domain.Pair(pair))
+		} else {
+			t.l1.deleteSnapshot(inv.Key)
+		}
+	}
+}
+
+func (t *TieredCache) invalidate(ctx context.Context, key string) {
+	payload, err := json.Marshal(invalidation{Origin: t.origin, Key: key})
+	if err != nil {
+		return
+	}
+	if err := t.l2.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		log.GetInstance().Debug("Failed to publish cache invalidation for %s: %v", key, err)
+	}
+}
+
+// record counts a lookup in tier and passes its result through.
+func record(tier string, hit bool) bool {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	tierLookups.WithLabelValues(tier, result).Inc()
+	return hit
+}
+
+func (t *TieredCache) Get(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	if ltp, ok := t.l1.Get(ctx, pair); record("l1", ok) {
+		return ltp, true
+	}
+	ltp, ok := t.l2.Get(ctx, pair)
+	if record("l2", ok) {
+		t.l1.Set(ctx, pair, ltp)
+	}
+	return ltp, ok
+}
+
+func (t *TieredCache) Set(ctx context.Context, pair domain.Pair, ltp domain.LTP) {
+	t.l2.Set(ctx, pair, ltp)
+	t.l1.Set(ctx, pair, ltp)
+	t.invalidate(ctx, priceKeyPrefix+string(pair))
+}
+
+// GetLastKnown reads L1, which sees every write and every L2 hit and so knows at least as much as L2.
+func (t *TieredCache) GetLastKnown(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	return t.l1.GetLastKnown(ctx, pair)
+}
+
+func (t *TieredCache) GetTicker(ctx context.Context, pair domain.Pair) (domain.Ticker, bool) {
+	if ticker, ok := t.l1.GetTicker(ctx, pair); record("l1", ok) {
+		return ticker, true
+	}
+	ticker, ok := t.l2.GetTicker(ctx, pair)
+	if record("l2", ok) {
+		t.l1.SetTicker(ctx, pair, ticker)
+	}
+	return ticker, ok
+}
+
+func (t *TieredCache) SetTicker(ctx context.Context, pair domain.Pair, ticker domain.Ticker) {
+	t.l2.SetTicker(ctx, pair, ticker)
+	t.l1.SetTicker(ctx, pair, ticker)
+	t.invalidate(ctx, tickerKey(pair))
+}
+
+func (t *TieredCache) GetOrderBook(ctx context.Context, pair domain.Pair) (domain.OrderBook, bool) {
+	if book, ok := t.l1.GetOrderBook(ctx, pair); record("l1", ok) {
+		return book, true
+	}
+	book, ok := t.l2.GetOrderBook(ctx, pair)
+	if record("l2", ok) {
+		t.l1.SetOrderBook(ctx, pair, book)
+	}
+	return book, ok
+}
+
+func (t *TieredCache) SetOrderBook(ctx context.Context, pair domain.Pair, book domain.OrderBook) {
+	t.l2.SetOrderBook(ctx, pair, book)
+	t.l1.SetOrderBook(ctx, pair, book)
+	t.invalidate(ctx, orderBookKey(pair))
+}
+
+func (t *TieredCache) GetCandles(ctx context.Context, pair domain.Pair, interval int) (domain.CandleSeries, bool) {
+	if series, ok := t.l1.GetCandles(ctx, pair, interval); record("l1", ok) {
+		return series, true
+	}
+	series, ok := t.l2.GetCandles(ctx, pair, interval)
+	if record("l2", ok) {
+		t.l1.SetCandles(ctx, series)
+	}
+	return series, ok
+}
+
+func (t *TieredCache) SetCandles(ctx context.Context, series domain.CandleSeries) {
+	t.l2.SetCandles(ctx, series)
+	t.l1.SetCandles(ctx, series)
+	t.invalidate(ctx, candlesKey(series.Pair, series.Interval))
+}
+
+func (t *TieredCache) CheckConnectivity() bool {
+	return t.l2.CheckConnectivity()
+}
+
+// Close stops listening for invalidations and closes the Redis connection.
+func (t *TieredCache) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		err = t.pubsub.Close()
+		<-t.done
+		if cerr := t.l2.Close(); err == nil {
+			err = cerr
+		}
+	})
+	return err
+}
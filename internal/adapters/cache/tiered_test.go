@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTieredCache(t *testing.T, addr string) *TieredCache {
+	t.Helper()
+	l1 := NewInMemoryCache(time.Minute)
+	l1.SetMaxEntries(100)
+	c := NewTieredCache(l1, NewRedisCache(addr, "", 0, time.Minute))
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestTieredCache_ReadThroughAndWriteThrough(t *testing.T) {
+	server := miniredis.RunT(t)
+	c := newTestTieredCache(t, server.Addr())
+	ctx := context.Background()
+
+	ltp := domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now().UTC()}
+	c.Set(ctx, "BTC/USD", ltp)
+	assert.True(t, server.Exists("BTC/USD"), "writes must reach Redis")
+
+	l1Hits := testutil.ToFloat64(tierLookups.WithLabelValues("l1", "hit"))
+	got, ok := c.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.True(t, ltp.Amount.Equal(got.Amount))
+	assert.Equal(t, l1Hits+1, testutil.ToFloat64(tierLookups.WithLabelValues("l1", "hit")))
+
+	c.l1.Delete(ctx, "BTC/USD")
+	l2Hits := testutil.ToFloat64(tierLookups.WithLabelValues("l2", "hit"))
+	_, ok = c.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, l2Hits+1, testutil.ToFloat64(tierLookups.WithLabelValues("l2", "hit")))
+	_, ok = c.l1.Get(ctx, "BTC/USD")
+	assert.True(t, ok, "an L2 hit must fill L1")
+
+	_, ok = c.Get(ctx, "BTC/EUR")
+	assert.False(t, ok)
+}
+
+func TestTieredCache_InvalidatesOtherReplicas(t *testing.T) {
+	server := miniredis.RunT(t)
+	a := newTestTieredCache(t, server.Addr())
+	b := newTestTieredCache(t, server.Addr())
+	ctx := context.Background()
+
+	old := domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now().UTC()}
+	b.Set(ctx, "BTC/USD", old)
+	b.SetTicker(ctx, "BTC/USD", domain.Ticker{Pair: "BTC/USD", Last: decimal.NewFromInt(50000)})
+
+	// Wait for both subscriptions to be active before publishing.
+	require.Eventually(t, func() bool {
+		return len(server.PubSubChannels(invalidationChannel)) == 1 && server.PubSubNumSub(invalidationChannel)[invalidationChannel] == 2
+	}, time.Second, 5*time.Millisecond)
+
+	a.Set(ctx, "BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(51000), Timestamp: time.Now().UTC()})
+	a.SetTicker(ctx, "BTC/USD", domain.Ticker{Pair: "BTC/USD", Last: decimal.NewFromInt(51000)})
+
+	require.Eventually(t, func() bool {
+		_, priceCached := b.l1.Get(ctx, "BTC/USD")
+		_, tickerCached := b.l1.GetTicker(ctx, "BTC/USD")
+		return !priceCached && !tickerCached
+	}, time.Second, 5*time.Millisecond)
+
+	got, ok := b.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, "51000", got.Amount.String())
+	_, ok = a.l1.Get(ctx, "BTC/USD")
+	assert.True(t, ok, "a replica ignores its own invalidations")
+}
+
+func TestInMemoryCache_MaxEntriesEvictsClosestToExpiry(t *testing.T) {
+	c := NewInMemoryCache(time.Minute)
+	c.SetMaxEntries(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "BTC/USD", domain.LTP{Pair: "BTC/USD"})
+	c.Set(ctx, "BTC/EUR", domain.LTP{Pair: "BTC/EUR"})
+	c.Set(ctx, "BTC/USD", domain.LTP{Pair: "BTC/USD"})
+	c.Set(ctx, "BTC/CHF", domain.LTP{Pair: "BTC/CHF"})
+
+	_, ok := c.Get(ctx, "BTC/EUR")
+	assert.False(t, ok)
+	_, ok = c.Get(ctx, "BTC/USD")
+	assert.True(t, ok)
+	_, ok = c.Get(ctx, "BTC/CHF")
+	assert.True(t, ok)
+}
+
+func TestCaches_LastKnownSkipsFailures(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx := context.Background()
+	good := domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now().UTC()}
+	failure := domain.LTP{Pair: "BTC/USD", Error: "upstream unavailable", Timestamp: time.Now().UTC()}
+
+	for name, c := range map[string]domain.Cache{
+		"memory": NewInMemoryCache(time.Minute),
+		"redis":  NewRedisCache(server.Addr(), "", 0, time.Minute),
+		"tiered": newTestTieredCache(t, server.Addr()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			lastKnown := c.(domain.LastKnownCache)
+			_, ok := lastKnown.GetLastKnown(ctx, "BTC/USD")
+			assert.False(t, ok)
+
+			c.Set(ctx, "BTC/USD", good)
+			c.Set(ctx, "BTC/USD", failure)
+
+			got, ok := lastKnown.GetLastKnown(ctx, "BTC/USD")
+			require.True(t, ok)
+			assert.Equal(t, "50000", got.Amount.String())
+		})
+		server.FlushAll()
+	}
+}
@@ -0,0 +1,231 @@
+package circuitbreaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+const ErrUpstreamUnavailable = "upstream unavailable: circuit open"
+
+var breakerState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "circuit_breaker_state",
+	Help: "Circuit breaker state per upstream (0=closed, 1=half-open, 2=open)",
+}, []string{"name"})
+
+type Fetcher interface {
+	Fetch(ctx context.Context, pair domain.Pair) domain.LTP
+}
+
+type Options struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+}
+
+// Breaker stops calling an upstream after repeated failures and lets a single trial through once the cool-down ends.
+// While open it fails fast; serving a fallback price is left to the service, which marks it stale.
+type Breaker struct {
+	name     string
+	upstream Fetcher
+	opts     Options
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func New(name string, upstream Fetcher, opts Options) *Breaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = 5
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = 30 * time.Second
+	}
+	b := &Breaker{
+		name:     name,
+		upstream: upstream,
+		opts:     opts,
+	}
+	breakerState.WithLabelValues(name).Set(float64(StateClosed))
+	return b
+}
+
+// Markets passes on the upstream's market list, if it has one.
+func (b *Breaker) Markets() []domain.Pair {
+	if m, ok := b.upstream.(interface{ Markets() []domain.Pair }); ok {
+		return m.Markets()
+	}
+	return nil
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	if !b.allow() {
+		return b.rejected(pair)
+	}
+
+	ltp := b.upstream.Fetch(ctx, pair)
+
+	switch {
+	case ctx.Err() != nil || ltp.IsRateLimited():
+		// The caller gave up or our own request budget ran out; neither says anything about the upstream.
+		b.release()
+	case ltp.Error == "" || ltp.IsUnsupported():
+		b.onSuccess()
+	default:
+		b.onFailure()
+	}
+	return ltp
+}
+
+// FetchMany forwards a batch to upstreams that support it, counting the whole batch as one call.
+func (b *Breaker) FetchMany(ctx context.Context, pairs []domain.Pair) []domain.LTP {
+	batcher, ok := b.upstream.(interface {
+		FetchMany(context.Context, []domain.Pair) []domain.LTP
+	})
+	if !ok {
+		out := make([]domain.LTP, len(pairs))
+		for i, pair := range pairs {
+			out[i] = b.Fetch(ctx, pair)
+		}
+		return out
+	}
+
+	if !b.allow() {
+		out := make([]domain.LTP, len(pairs))
+		for i, pair := range pairs {
+			out[i] = b.rejected(pair)
+		}
+		return out
+	}
+
+	ltps := batcher.FetchMany(ctx, pairs)
+	if ctx.Err() != nil {
+		b.release()
+		return ltps
+	}
+
+	answered, failed := false, false
+	for _, ltp := range ltps {
+		switch {
+		case ltp.IsRateLimited():
+		case ltp.Error == "" || ltp.IsUnsupported():
+			answered = true
+			b.onSuccess()
+		default:
+			failed = true
+		}
+	}
+	switch {
+	case answered:
+	case failed:
+		b.onFailure()
+	default:
+		b.release()
+	}
+	return ltps
+}
+
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.opts.Cooldown {
+			return false
+		}
+		b.setState(StateHalfOpen)
+		b.trial = true
+		return true
+	case StateHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+func (b *Breaker) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+	if b.state != StateClosed {
+		log.GetInstance().Info("Circuit breaker %s closed", b.name)
+		b.setState(StateClosed)
+	}
+}
+
+func (b *Breaker) onFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.state == StateHalfOpen || b.failures >= b.opts.FailureThreshold {
+		if b.state != StateOpen {
+			log.GetInstance().Warn("Circuit breaker %s opened after %d failures", b.name, b.failures)
+		}
+		b.openedAt = time.Now()
+		b.setState(StateOpen)
+	}
+}
+
+func (b *Breaker) rejected(pair domain.Pair) domain.LTP {
+	return domain.LTP{
+		Pair:      pair,
+		Error:     fmt.Sprintf("%s (%s)", ErrUpstreamUnavailable, b.name),
+		Timestamp: time.Now().UTC(),
+		Source:    b.name,
+	}
+}
+
+func (b *Breaker) setState(s State) {
+	b.state = s
+	breakerState.WithLabelValues(b.name).Set(float64(s))
+}
@@ -0,0 +1,56 @@
+package circuitbreaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker_OpensAndRecovers(t *testing.T) {
+	upstream := mocks.NewMockMarketDataProvider()
+	breaker := New("kraken", upstream, Options{
+		FailureThreshold: 2,
+		Cooldown:         30 * time.Millisecond,
+	})
+
+	pair := domain.Pair("BTC/USD")
+	good := domain.LTP{Pair: pair, Amount: decimal.NewFromInt(50000), Timestamp: time.Now()}
+	upstream.SetResponse(pair, good)
+	assert.Equal(t, good, breaker.Fetch(context.Background(), pair))
+
+	upstream.SetResponse(pair, domain.LTP{Pair: pair, Error: "timeout", Timestamp: time.Now()})
+	breaker.Fetch(context.Background(), pair)
+	breaker.Fetch(context.Background(), pair)
+	assert.Equal(t, StateOpen, breaker.State())
+
+	// While open the upstream is not called; a cached price is never replayed as if it were fresh.
+	assert.Contains(t, breaker.Fetch(context.Background(), pair).Error, ErrUpstreamUnavailable)
+	assert.Equal(t, 3, upstream.GetCallCount(pair))
+
+	time.Sleep(40 * time.Millisecond)
+	breaker.Fetch(context.Background(), pair)
+	assert.Equal(t, StateOpen, breaker.State(), "failed trial must reopen the circuit")
+
+	time.Sleep(40 * time.Millisecond)
+	upstream.SetResponse(pair, good)
+	assert.Equal(t, good, breaker.Fetch(context.Background(), pair))
+	assert.Equal(t, StateClosed, breaker.State())
+}
+
+func TestBreaker_FastFailsWhileOpen(t *testing.T) {
+	upstream := mocks.NewMockMarketDataProvider()
+	breaker := New("kraken", upstream, Options{FailureThreshold: 1, Cooldown: time.Minute})
+
+	pair := domain.Pair("BTC/EUR")
+	upstream.SetResponse(pair, domain.LTP{Pair: pair, Error: "boom", Timestamp: time.Now()})
+	breaker.Fetch(context.Background(), pair)
+
+	ltp := breaker.Fetch(context.Background(), pair)
+	assert.Contains(t, ltp.Error, ErrUpstreamUnavailable)
+	assert.Equal(t, 1, upstream.GetCallCount(pair))
+}
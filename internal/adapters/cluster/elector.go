@@ -0,0 +1,129 @@
+package cluster
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// Only the holder may extend or drop the lock; anyone else's value means the lease was lost.
+var (
+	renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+)
+
+// Elector holds a Redis lock with a renewable lease. Whoever holds it is the leader; when the leader
+// stops renewing, the lease expires and another replica takes over.
+type Elector struct {
+	client redis.UniversalClient
+	key    string
+	id     string
+	lease  time.Duration
+	leader atomic.Bool
+
+	once sync.Once
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewElector(client redis.UniversalClient, key string, lease time.Duration) *Elector {
+	return &Elector{
+		client: client,
+		key:    key,
+		id:     newID(),
+		lease:  lease,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Start campaigns in the background, renewing every third of the lease while leading.
+func (e *Elector) Start() {
+	go func() {
+		defer close(e.done)
+		t := time.NewTicker(e.lease / 3)
+		defer t.Stop()
+		for {
+			e.step()
+			select {
+			case <-t.C:
+			case <-e.stop:
+				e.release()
+				return
+			}
+		}
+	}()
+}
+
+// Stop gives up leadership at once rather than letting the lease run out.
+func (e *Elector) Stop() {
+	e.once.Do(func() {
+		close(e.stop)
+		<-e.done
+	})
+}
+
+func (e *Elector) step() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.lease/3)
+	defer cancel()
+
+	if e.leader.Load() {
+		renewed, err := renewScript.Run(ctx, e.client, []string{e.key}, e.id, e.lease.Milliseconds()).Int()
+		// Without a confirmed renewal another replica may already lead; step down to be safe.
+		switch {
+		case err != nil:
+			e.leader.Store(false)
+			log.GetInstance().Warn("Stepping down as refresher leader, renewal failed: %v", err)
+		case renewed == 0:
+			e.leader.Store(false)
+			log.GetInstance().Warn("Stepping down as refresher leader, lease expired")
+		}
+		return
+	}
+
+	acquired, err := e.client.SetNX(ctx, e.key, e.id, e.lease).Result()
+	if err != nil {
+		log.GetInstance().Debug("Leader election failed: %v", err)
+		return
+	}
+	if acquired {
+		e.leader.Store(true)
+		log.GetInstance().Info("Acquired refresher leadership")
+	}
+}
+
+func (e *Elector) release() {
+	if !e.leader.Swap(false) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := releaseScript.Run(ctx, e.client, []string{e.key}, e.id).Err(); err != nil {
+		log.GetInstance().Warn("Failed to release refresher leadership: %v", err)
+	}
+}
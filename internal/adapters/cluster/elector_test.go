@@ -0,0 +1,59 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, server *miniredis.Miniredis) *redis.Client {
+	t.Helper()
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestElector_FailsOverWhenLeaseExpires(t *testing.T) {
+	server := miniredis.RunT(t)
+	a := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+	b := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+
+	a.step()
+	b.step()
+	require.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader(), "only one replica may hold the lease")
+
+	// a renews in time and keeps the lock.
+	server.FastForward(2 * time.Second)
+	a.step()
+	server.FastForward(2 * time.Second)
+	b.step()
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// a stalls past its lease: b takes over and a steps down on its next renewal.
+	server.FastForward(3 * time.Second)
+	b.step()
+	a.step()
+	assert.True(t, b.IsLeader())
+	assert.False(t, a.IsLeader())
+}
+
+func TestElector_StopReleasesLeadership(t *testing.T) {
+	server := miniredis.RunT(t)
+	a := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+	a.Start()
+	require.Eventually(t, a.IsLeader, time.Second, 5*time.Millisecond)
+
+	a.Stop()
+	assert.False(t, a.IsLeader())
+	assert.False(t, server.Exists("leader"))
+
+	b := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+	b.step()
+	assert.True(t, b.IsLeader())
+}
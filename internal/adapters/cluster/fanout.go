@@ -0,0 +1,209 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	kindPrice   = "price"
+	kindRequest = "request"
+	kindReply   = "reply"
+
+	// relayTimeout bounds both a follower's wait for a reply and the leader's upstream call answering it.
+	relayTimeout = 10 * time.Second
+	priceBuffer  = 256
+)
+
+// message is what replicas exchange on the channel. Prices are the leader's refresh results; a request
+// asks the leader to fetch a pair upstream for a follower, and the reply carries that fetch's result.
+type message struct {
+	Kind   string      `json:"kind"`
+	Origin string      `json:"origin"`
+	Pair   domain.Pair `json:"pair,omitempty"`
+	LTP    domain.LTP  `json:"ltp"`
+}
+
+// FanOut publishes the prices the leader's background refresh records on a Redis channel and feeds
+// them into followers' services. With a Relay in front of the upstream, followers also ask the leader
+// for prices they miss, so only the leader talks to the exchange.
+type FanOut struct {
+	client   redis.UniversalClient
+	elector  *Elector
+	channel  string
+	origin   string
+	service  *application.LTPService
+	upstream application.MarketDataProvider
+	prices   chan domain.LTP
+	requests singleflight.Group
+
+	mu      sync.Mutex
+	waiters map[domain.Pair][]chan domain.LTP
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewFanOut(client redis.UniversalClient, e *Elector, channel string) *FanOut {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &FanOut{
+		client:  client,
+		elector: e,
+		channel: channel,
+		origin:  newID(),
+		prices:  make(chan domain.LTP, priceBuffer),
+		waiters: make(map[domain.Pair][]chan domain.LTP),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start attaches the fan-out to the service's refreshes. A Relay must be set up before this.
+func (f *FanOut) Start(s *application.LTPService) {
+	f.service = s
+	s.SetRefreshListener(f.offer)
+	pubsub := f.client.Subscribe(f.ctx, f.channel)
+	f.wg.Add(2)
+	go f.publish()
+	go f.consume(pubsub)
+}
+
+func (f *FanOut) Stop() {
+	f.cancel()
+	f.wg.Wait()
+}
+
+// offer queues a refreshed price without holding up the refresh; if publishing falls behind, the
+// followers get the pair again on the next refresh.
+func (f *FanOut) offer(ltp domain.LTP) {
+	select {
+	case f.prices <- ltp:
+	default:
+		log.GetInstance().Warn("Price fan-out fell behind, dropping %s", ltp.Pair)
+	}
+}
+
+func (f *FanOut) publish() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case ltp := <-f.prices:
+			if !f.elector.IsLeader() {
+				continue
+			}
+			if err := f.send(message{Kind: kindPrice, LTP: ltp}); err != nil {
+				log.GetInstance().Warn("Failed to publish price for %s: %v", ltp.Pair, err)
+			}
+		}
+	}
+}
+
+func (f *FanOut) send(m message) error {
+	m.Origin = f.origin
+	payload, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return f.client.Publish(f.ctx, f.channel, payload).Err()
+}
+
+func (f *FanOut) consume(pubsub *redis.PubSub) {
+	defer f.wg.Done()
+	defer func() {
+		if err := pubsub.Close(); err != nil {
+			log.GetInstance().Debug("Error closing price subscription: %v", err)
+		}
+	}()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			var m message
+			if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+				log.GetInstance().Debug("Ignoring malformed price message: %v", err)
+				continue
+			}
+			switch m.Kind {
+			case kindRequest:
+				if f.elector.IsLeader() && f.upstream != nil {
+					f.wg.Add(1)
+					go f.answer(m.Pair)
+				}
+			case kindReply:
+				f.deliver(m.Pair, m.LTP)
+			default:
+				// A leader never takes prices back in, so two replicas briefly both leading cannot echo each other.
+				if m.Origin == f.origin || f.elector.IsLeader() {
+					continue
+				}
+				f.service.Ingest(f.ctx, m.LTP)
+			}
+		}
+	}
+}
+
+// answer fetches a pair upstream for the followers; requests for a pair already being fetched share the reply.
+func (f *FanOut) answer(pair domain.Pair) {
+	defer f.wg.Done()
+	f.requests.Do(string(pair), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(f.ctx, relayTimeout)
+		defer cancel()
+		ltp := f.upstream.Fetch(ctx, pair)
+		if f.ctx.Err() != nil {
+			return nil, nil
+		}
+		if err := f.send(message{Kind: kindReply, Pair: pair, LTP: ltp}); err != nil {
+			log.GetInstance().Warn("Failed to reply with price for %s: %v", pair, err)
+		}
+		return nil, nil
+	})
+}
+
+func (f *FanOut) wait(pair domain.Pair) chan domain.LTP {
+	ch := make(chan domain.LTP, 1)
+	f.mu.Lock()
+	f.waiters[pair] = append(f.waiters[pair], ch)
+	f.mu.Unlock()
+	return ch
+}
+
+func (f *FanOut) stopWaiting(pair domain.Pair, ch chan domain.LTP) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	waiters := f.waiters[pair]
+	for i, w := range waiters {
+		if w == ch {
+			f.waiters[pair] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(f.waiters[pair]) == 0 {
+		delete(f.waiters, pair)
+	}
+}
+
+func (f *FanOut) deliver(pair domain.Pair, ltp domain.LTP) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ch := range f.waiters[pair] {
+		ch <- ltp
+	}
+	delete(f.waiters, pair)
+}
@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/cache"
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingProvider struct {
+	calls atomic.Int32
+}
+
+func (p *countingProvider) Fetch(_ context.Context, pair domain.Pair) domain.LTP {
+	p.calls.Add(1)
+	return domain.LTP{Pair: pair, Amount: decimal.NewFromInt(50000), Timestamp: time.Now().UTC()}
+}
+
+// startPair runs a leader and a follower on one channel, each service fetching through its fan-out's relay.
+func startPair(t *testing.T, leaderProvider, followerProvider application.MarketDataProvider) (leader, follower *application.LTPService, followerCache *cache.InMemoryCache) {
+	t.Helper()
+	server := miniredis.RunT(t)
+
+	a := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+	b := NewElector(newTestClient(t, server), "leader", 3*time.Second)
+	a.step()
+	b.step()
+	require.True(t, a.IsLeader())
+
+	fa := NewFanOut(newTestClient(t, server), a, "prices")
+	fb := NewFanOut(newTestClient(t, server), b, "prices")
+	followerCache = cache.NewInMemoryCache(time.Minute)
+	leader = application.NewLTPService(cache.NewInMemoryCache(time.Minute), fa.Relay(leaderProvider), time.Minute)
+	follower = application.NewLTPService(followerCache, fb.Relay(followerProvider), time.Minute)
+	fa.Start(leader)
+	fb.Start(follower)
+	t.Cleanup(fa.Stop)
+	t.Cleanup(fb.Stop)
+	require.Eventually(t, func() bool {
+		return server.PubSubNumSub("prices")["prices"] == 2
+	}, time.Second, 5*time.Millisecond)
+	return leader, follower, followerCache
+}
+
+func TestFanOut_FollowersTakePricesFromLeader(t *testing.T) {
+	ctx := context.Background()
+	leaderProvider, followerProvider := &countingProvider{}, &countingProvider{}
+	leader, follower, followerCache := startPair(t, leaderProvider, followerProvider)
+
+	sub := follower.Subscribe([]domain.Pair{"BTC/USD"}, 0)
+	defer follower.Unsubscribe(sub)
+
+	leader.RefreshPairs(ctx, []domain.Pair{"BTC/USD"})
+
+	select {
+	case ev := <-sub.Events:
+		assert.Equal(t, "50000", ev.LTP.Amount.String())
+	case <-time.After(time.Second):
+		t.Fatal("follower did not receive the leader's price")
+	}
+	got, ok := followerCache.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, "50000", got.Amount.String())
+	assert.Equal(t, int32(1), leaderProvider.calls.Load())
+	assert.Zero(t, followerProvider.calls.Load(), "followers must not poll the exchange")
+}
+
+func TestFanOut_OnlyRefreshesArePublished(t *testing.T) {
+	ctx := context.Background()
+	leader, follower, _ := startPair(t, &countingProvider{}, &countingProvider{})
+
+	sub := follower.Subscribe(nil, 0)
+	defer follower.Unsubscribe(sub)
+
+	ltp := leader.GetLTP(ctx, "BTC/EUR")
+	require.Empty(t, ltp.Error)
+	leader.RefreshPairs(ctx, []domain.Pair{"BTC/USD"})
+
+	select {
+	case ev := <-sub.Events:
+		assert.Equal(t, domain.Pair("BTC/USD"), ev.LTP.Pair, "a price fetched for a caller must not be fanned out")
+	case <-time.After(time.Second):
+		t.Fatal("follower did not receive the refreshed price")
+	}
+}
+
+func TestFanOut_FollowerMissesAreFetchedByLeader(t *testing.T) {
+	ctx := context.Background()
+	leaderProvider, followerProvider := &countingProvider{}, &countingProvider{}
+	_, follower, _ := startPair(t, leaderProvider, followerProvider)
+
+	ltps := follower.GetLTPs(ctx, []domain.Pair{"BTC/USD", "ETH/USD"})
+	require.Len(t, ltps, 2)
+	for _, ltp := range ltps {
+		assert.Empty(t, ltp.Error)
+		assert.Equal(t, "50000", ltp.Amount.String())
+	}
+	assert.Equal(t, int32(2), leaderProvider.calls.Load())
+	assert.Zero(t, followerProvider.calls.Load(), "followers must not call the exchange on a miss")
+}
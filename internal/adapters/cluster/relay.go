@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+// Relay stands in for an upstream provider on every replica. The leader calls the upstream itself;
+// a follower asks the leader over the fan-out channel and waits for its reply.
+type Relay struct {
+	fanOut   *FanOut
+	upstream application.MarketDataProvider
+}
+
+// Relay routes followers' fetches from upstream through the leader. It must be called before Start.
+func (f *FanOut) Relay(upstream application.MarketDataProvider) *Relay {
+	f.upstream = upstream
+	return &Relay{fanOut: f, upstream: upstream}
+}
+
+// Markets passes on the upstream's market list, if it has one.
+func (r *Relay) Markets() []domain.Pair {
+	if m, ok := r.upstream.(interface{ Markets() []domain.Pair }); ok {
+		return m.Markets()
+	}
+	return nil
+}
+
+func (r *Relay) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	if r.fanOut.elector.IsLeader() {
+		return r.upstream.Fetch(ctx, pair)
+	}
+	return r.request(ctx, pair)
+}
+
+// FetchMany keeps the leader's batches in one upstream call; a follower asks for each pair concurrently.
+func (r *Relay) FetchMany(ctx context.Context, pairs []domain.Pair) []domain.LTP {
+	if batcher, ok := r.upstream.(application.BatchProvider); ok && r.fanOut.elector.IsLeader() {
+		return batcher.FetchMany(ctx, pairs)
+	}
+	out := make([]domain.LTP, len(pairs))
+	var wg sync.WaitGroup
+	for i, pair := range pairs {
+		wg.Add(1)
+		go func(i int, pair domain.Pair) {
+			defer wg.Done()
+			out[i] = r.Fetch(ctx, pair)
+		}(i, pair)
+	}
+	wg.Wait()
+	return out
+}
+
+func (r *Relay) request(ctx context.Context, pair domain.Pair) domain.LTP {
+	f := r.fanOut
+	reply := f.wait(pair)
+	defer f.stopWaiting(pair, reply)
+
+	if err := f.send(message{Kind: kindRequest, Pair: pair}); err != nil {
+		return relayError(pair, fmt.Sprintf("cluster relay: %v", err))
+	}
+
+	timer := time.NewTimer(relayTimeout)
+	defer timer.Stop()
+	select {
+	case ltp := <-reply:
+		return ltp
+	case <-ctx.Done():
+		return relayError(pair, fmt.Sprintf("%v: %v", domain.ErrCanceled, ctx.Err()))
+	case <-timer.C:
+		return relayError(pair, fmt.Sprintf("cluster relay: no reply from the leader for %s", pair))
+	}
+}
+
+func relayError(pair domain.Pair, msg string) domain.LTP {
+	return domain.LTP{Pair: pair, Error: msg, Timestamp: time.Now().UTC()}
+}
@@ -0,0 +1,50 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/exchange"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const Source = "coinbase"
+
+type Client struct {
+	baseURL string
+	rest    *exchange.REST
+	symbols map[domain.Pair]string
+}
+
+// NewClient builds a Coinbase Exchange client; symbols overrides the default BTC/USD -> BTC-USD mapping.
+func NewClient(baseURL string, timeout uint, symbols map[domain.Pair]string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		rest:    exchange.NewREST(Source, "Coinbase", timeout, nil),
+		symbols: symbols,
+	}
+}
+
+type tickerResp struct {
+	Price  string    `json:"price"`
+	Volume string    `json:"volume"`
+	Time   time.Time `json:"time"`
+}
+
+func (c *Client) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	var parsed tickerResp
+	url := fmt.Sprintf("%s/products/%s/ticker", c.baseURL, c.productID(pair))
+	if ltp, ok := c.rest.GetJSON(ctx, pair, url, &parsed); !ok {
+		return ltp
+	}
+	return c.rest.PriceLTP(pair, parsed.Price, parsed.Volume, parsed.Time)
+}
+
+func (c *Client) productID(pair domain.Pair) string {
+	if symbol, ok := c.symbols[pair]; ok {
+		return symbol
+	}
+	return strings.ReplaceAll(strings.ToUpper(string(pair)), "/", "-")
+}
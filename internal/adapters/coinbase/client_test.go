@@ -0,0 +1,72 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		check  func(t *testing.T, ltp domain.LTP)
+	}{
+		{
+			name:   "success",
+			status: http.StatusOK,
+			body:   `{"price":"65000.12","volume":"1234.5","time":"2025-01-02T03:04:05.000000Z"}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Empty(t, ltp.Error)
+				assert.Equal(t, "65000.12", ltp.Amount.String())
+				assert.Equal(t, "1234.5", ltp.Volume.String())
+				assert.Equal(t, 2025, ltp.Timestamp.Year())
+				assert.Equal(t, Source, ltp.Source)
+			},
+		},
+		{
+			name:   "not found is unsupported",
+			status: http.StatusNotFound,
+			body:   `{"message":"NotFound"}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsUnsupported(), ltp.Error)
+			},
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   `{"message":"Public rate limit exceeded"}`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.True(t, ltp.IsRateLimited(), ltp.Error)
+			},
+		},
+		{
+			name:   "malformed body",
+			status: http.StatusOK,
+			body:   `{"price":`,
+			check: func(t *testing.T, ltp domain.LTP) {
+				assert.Contains(t, ltp.Error, "invalid Coinbase response")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/products/BTC-USD/ticker", r.URL.Path)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			ltp := NewClient(server.URL, 5, nil).Fetch(context.Background(), "BTC/USD")
+			assert.Equal(t, domain.Pair("BTC/USD"), ltp.Pair)
+			tt.check(t, ltp)
+		})
+	}
+}
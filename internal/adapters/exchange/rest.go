@@ -0,0 +1,112 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Ticker responses are small; anything larger is not a ticker.
+const maxBodySize = 1 << 20
+
+// REST is the HTTP and error mapping shared by the single-ticker exchange adapters.
+type REST struct {
+	source string
+	name   string
+	http   *http.Client
+
+	// unsupported reports whether a non-200 answer means the exchange does not list the pair.
+	unsupported func(status int, body []byte) bool
+}
+
+// NewREST builds the shared client; name is how the exchange is spelled in messages. A nil unsupported
+// treats 404 as an unlisted pair.
+func NewREST(source, name string, timeout uint, unsupported func(status int, body []byte) bool) *REST {
+	if unsupported == nil {
+		unsupported = func(status int, _ []byte) bool { return status == http.StatusNotFound }
+	}
+	return &REST{
+		source:      source,
+		name:        name,
+		http:        &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		unsupported: unsupported,
+	}
+}
+
+// GetJSON fetches url and decodes a 200 answer into out. On failure it returns false with the LTP the
+// adapter should return: unlisted pairs and 429s carry the domain prefixes callers classify errors by.
+func (r *REST) GetJSON(ctx context.Context, pair domain.Pair, url string, out interface{}) (domain.LTP, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return r.ErrorLTP(pair, err.Error()), false
+	}
+	resp, err := r.http.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return r.ErrorLTP(pair, fmt.Sprintf("%v: %v", domain.ErrCanceled, ctx.Err())), false
+		}
+		log.GetInstance().Warn("Failed to fetch pair %s from %s: %v", pair, r.name, err)
+		return r.ErrorLTP(pair, err.Error()), false
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.GetInstance().Error("Error close HTTP request: %v", err)
+		}
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return r.ErrorLTP(pair, fmt.Sprintf("reading %s response: %v", r.name, err)), false
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return r.ErrorLTP(pair, fmt.Sprintf("%s: %s request budget exhausted", domain.RateLimitedPrefix, r.source)), false
+	case resp.StatusCode != http.StatusOK && r.unsupported(resp.StatusCode, body):
+		return r.ErrorLTP(pair, fmt.Sprintf("%s: pair not supported: %s", domain.UnsupportedPairPrefix, pair)), false
+	case resp.StatusCode != http.StatusOK:
+		return r.ErrorLTP(pair, fmt.Sprintf("%s error: status %d", r.source, resp.StatusCode)), false
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return r.ErrorLTP(pair, fmt.Sprintf("invalid %s response: %v", r.name, err)), false
+	}
+	return domain.LTP{}, true
+}
+
+// PriceLTP builds the LTP for a decimal price string; an unparsable volume is left out.
+func (r *REST) PriceLTP(pair domain.Pair, price, volume string, ts time.Time) domain.LTP {
+	amount, err := decimal.NewFromString(price)
+	if err != nil {
+		return r.ErrorLTP(pair, fmt.Sprintf("Invalid price format: %v", err))
+	}
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	ltp := domain.LTP{
+		Pair:      pair,
+		Amount:    amount,
+		Timestamp: ts.UTC(),
+		Source:    r.source,
+	}
+	if v, err := decimal.NewFromString(volume); err == nil {
+		ltp.Volume = &v
+	}
+	return ltp
+}
+
+func (r *REST) ErrorLTP(pair domain.Pair, msg string) domain.LTP {
+	return domain.LTP{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    r.source,
+	}
+}
@@ -0,0 +1,228 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	ltpv1 "github.com/FrancoRivero2025/go-exercise/api/ltp/v1"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server exposes LTPService over gRPC together with the standard health service.
+type Server struct {
+	ltpv1.UnimplementedLTPServiceServer
+
+	service *application.LTPService
+	pairs   []domain.Pair
+	grpc    *grpc.Server
+	health  *health.Server
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func NewServer(s *application.LTPService, pairs []domain.Pair, opts ...grpc.ServerOption) *Server {
+	srv := &Server{
+		service: s,
+		pairs:   pairs,
+		grpc:    grpc.NewServer(opts...),
+		health:  health.NewServer(),
+		done:    make(chan struct{}),
+	}
+	ltpv1.RegisterLTPServiceServer(srv.grpc, srv)
+	healthpb.RegisterHealthServer(srv.grpc, srv.health)
+	reflection.Register(srv.grpc)
+
+	srv.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	srv.health.SetServingStatus(ltpv1.LTPService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return srv
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	return s.grpc.Serve(lis)
+}
+
+// Shutdown reports NOT_SERVING, ends open WatchLTP streams and waits for in-flight calls until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		s.health.Shutdown()
+		close(s.done)
+	})
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) GetLTP(ctx context.Context, req *ltpv1.GetLTPRequest) (*ltpv1.GetLTPResponse, error) {
+	pair := domain.Pair(strings.TrimSpace(req.GetPair()))
+	if pair == "" {
+		return nil, status.Error(codes.InvalidArgument, "pair is required")
+	}
+
+	ltp, err := s.service.GetLTPFrom(ctx, strings.TrimSpace(req.GetSource()), pair)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	switch {
+	case ltp.IsEmpty():
+		return nil, status.Errorf(codes.NotFound, "pair %s not found", pair)
+	case ltp.IsUnsupported():
+		return nil, status.Error(codes.NotFound, ltp.Error)
+	case ltp.IsRateLimited():
+		return nil, status.Error(codes.ResourceExhausted, ltp.Error)
+	case ltp.Error != "" && ltp.Amount.IsZero():
+		return nil, status.Error(codes.Unavailable, ltp.Error)
+	}
+	return &ltpv1.GetLTPResponse{Ltp: toProto(ltp)}, nil
+}
+
+// GetLTPs mirrors GET /api/v1/ltp: per-pair failures are reported in each LTP's error field.
+func (s *Server) GetLTPs(ctx context.Context, req *ltpv1.GetLTPsRequest) (*ltpv1.GetLTPsResponse, error) {
+	ltps, err := s.service.GetLTPsFrom(ctx, strings.TrimSpace(req.GetSource()), parsePairs(req.GetPairs()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	resp := &ltpv1.GetLTPsResponse{Ltps: make([]*ltpv1.LTP, 0, len(ltps))}
+	for _, ltp := range ltps {
+		resp.Ltps = append(resp.Ltps, toProto(ltp))
+	}
+	return resp, nil
+}
+
+func (s *Server) ListPairs(ctx context.Context, _ *ltpv1.ListPairsRequest) (*ltpv1.ListPairsResponse, error) {
+	resp := &ltpv1.ListPairsResponse{
+		Pairs:   make([]string, 0, len(s.pairs)),
+		Sources: s.service.Sources(),
+	}
+	for _, pair := range s.pairs {
+		resp.Pairs = append(resp.Pairs, string(pair))
+	}
+	return resp, nil
+}
+
+// WatchLTP sends every price the service records for the requested pairs until the client cancels.
+// Resuming works like the SSE stream: when the resume point cannot be replayed the stream opens with a reset.
+func (s *Server) WatchLTP(req *ltpv1.WatchLTPRequest, stream grpc.ServerStreamingServer[ltpv1.LTPUpdate]) error {
+	lastEventID, reset, err := s.service.ParseEventID(strings.TrimSpace(req.GetResumeAfter()))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "resume_after must be an event_id sent by this stream")
+	}
+	if req.GetResumeAfter() == "" && req.GetLastEventId() != 0 {
+		// A bare id may have been issued before a restart, so it is treated like one from another epoch.
+		reset = true
+	}
+
+	sub := s.service.Subscribe(parsePairs(req.GetPairs()), lastEventID)
+	defer s.service.Unsubscribe(sub)
+
+	epoch := s.service.UpdatesEpoch()
+	if reset || sub.Reset {
+		if err := stream.Send(&ltpv1.LTPUpdate{Epoch: epoch, Resync: true}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-s.done:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case ev, ok := <-sub.Events:
+			if !ok {
+				if sub.Dropped() {
+					log.GetInstance().Info("Closed WatchLTP stream for slow client")
+					return status.Error(codes.ResourceExhausted, "client fell too far behind")
+				}
+				return nil
+			}
+			update := &ltpv1.LTPUpdate{Id: ev.ID, Ltp: toProto(ev.LTP), Epoch: epoch, EventId: s.service.EventID(ev.ID)}
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parsePairs(in []string) []domain.Pair {
+	res := make([]domain.Pair, 0, len(in))
+	seen := make(map[string]bool)
+	for _, p := range in {
+		pair := strings.TrimSpace(p)
+		if pair != "" && !seen[pair] {
+			res = append(res, domain.Pair(pair))
+			seen[pair] = true
+		}
+	}
+	return res
+}
+
+func toStatus(err error) error {
+	if errors.Is(err, application.ErrUnknownSource) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// toProto keeps decimals as strings so no precision is lost on the wire.
+func toProto(ltp domain.LTP) *ltpv1.LTP {
+	out := &ltpv1.LTP{
+		Pair:   string(ltp.Pair),
+		Error:  ltp.Error,
+		Source: ltp.Source,
+		Stale:  ltp.Stale,
+		AgeMs:  ltp.AgeMs,
+	}
+	if ltp.Error == "" || !ltp.Amount.IsZero() {
+		out.Amount = ltp.Amount.String()
+	}
+	if ltp.Volume != nil {
+		out.Volume = ltp.Volume.String()
+	}
+	if !ltp.Timestamp.IsZero() {
+		out.Timestamp = timestamppb.New(ltp.Timestamp)
+	}
+	if ltp.Derived != nil {
+		for _, leg := range ltp.Derived.Legs {
+			out.Legs = append(out.Legs, &ltpv1.Leg{
+				Pair:      string(leg.Pair),
+				Amount:    leg.Amount.String(),
+				Inverted:  leg.Inverted,
+				Timestamp: timestamppb.New(leg.Timestamp),
+				Source:    leg.Source,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	ltpv1 "github.com/FrancoRivero2025/go-exercise/api/ltp/v1"
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startServer(t *testing.T, service *application.LTPService) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(service, []domain.Pair{"BTC/USD", "BTC/EUR"})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return srv, conn
+}
+
+func TestServer_GetLTP(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	amount, _ := decimal.NewFromString("50123.123456789")
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: amount, Timestamp: time.Now(), Source: "kraken"})
+	provider.SetResponse("DOGE/XYZ", domain.LTP{Pair: "DOGE/XYZ", Error: domain.UnsupportedPairPrefix + ": DOGE/XYZ", Timestamp: time.Now()})
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+	_, conn := startServer(t, service)
+	client := ltpv1.NewLTPServiceClient(conn)
+	ctx := context.Background()
+
+	resp, err := client.GetLTP(ctx, &ltpv1.GetLTPRequest{Pair: "BTC/USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "50123.123456789", resp.GetLtp().GetAmount())
+	assert.Equal(t, "kraken", resp.GetLtp().GetSource())
+
+	_, err = client.GetLTP(ctx, &ltpv1.GetLTPRequest{Pair: "DOGE/XYZ"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetLTP(ctx, &ltpv1.GetLTPRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.GetLTP(ctx, &ltpv1.GetLTPRequest{Pair: "BTC/USD", Source: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetLTPsAndListPairs(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now()})
+	provider.SetResponse("BTC/EUR", domain.LTP{Pair: "BTC/EUR", Error: "upstream down", Timestamp: time.Now()})
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+	_, conn := startServer(t, service)
+	client := ltpv1.NewLTPServiceClient(conn)
+
+	resp, err := client.GetLTPs(context.Background(), &ltpv1.GetLTPsRequest{Pairs: []string{"BTC/USD", "BTC/EUR", "BTC/USD"}})
+	require.NoError(t, err)
+	require.Len(t, resp.GetLtps(), 2)
+	assert.Equal(t, "50000", resp.GetLtps()[0].GetAmount())
+	assert.Equal(t, "upstream down", resp.GetLtps()[1].GetError())
+	assert.Empty(t, resp.GetLtps()[1].GetAmount())
+
+	pairs, err := client.ListPairs(context.Background(), &ltpv1.ListPairsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC/USD", "BTC/EUR"}, pairs.GetPairs())
+}
+
+func TestServer_WatchLTP(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+	srv, conn := startServer(t, service)
+	client := ltpv1.NewLTPServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	stream, err := client.WatchLTP(ctx, &ltpv1.WatchLTPRequest{Pairs: []string{"BTC/USD"}})
+	require.NoError(t, err)
+
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now()})
+	provider.SetResponse("BTC/EUR", domain.LTP{Pair: "BTC/EUR", Amount: decimal.NewFromInt(45000), Timestamp: time.Now()})
+	// The subscription is registered once the stream handler runs; keep refreshing until it sees an update.
+	go func() {
+		for ctx.Err() == nil {
+			service.RefreshPairs(ctx, []domain.Pair{"BTC/EUR", "BTC/USD"})
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "BTC/USD", update.GetLtp().GetPair())
+	assert.Equal(t, "50000", update.GetLtp().GetAmount())
+	assert.NotZero(t, update.GetId())
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Second)
+	defer shutdownCancel()
+	require.NoError(t, srv.Shutdown(shutdownCtx))
+	for {
+		if _, err = stream.Recv(); err != nil {
+			break
+		}
+	}
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestServer_WatchLTPResume(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	_, conn := startServer(t, service)
+	client := ltpv1.NewLTPServiceClient(conn)
+	for i := int64(1); i <= 3; i++ {
+		service.Ingest(context.Background(), domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000 + i), Timestamp: time.Now()})
+	}
+	epoch := service.UpdatesEpoch()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	watch := func(req *ltpv1.WatchLTPRequest) ltpv1.LTPService_WatchLTPClient {
+		stream, err := client.WatchLTP(ctx, req)
+		require.NoError(t, err)
+		return stream
+	}
+
+	stream := watch(&ltpv1.WatchLTPRequest{ResumeAfter: epoch + "-1"})
+	for _, want := range []string{"50002", "50003"} {
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		assert.False(t, update.GetResync())
+		assert.Equal(t, epoch, update.GetEpoch())
+		assert.Equal(t, service.EventID(update.GetId()), update.GetEventId())
+		assert.Equal(t, want, update.GetLtp().GetAmount())
+	}
+
+	for _, req := range []*ltpv1.WatchLTPRequest{{ResumeAfter: "previous-2"}, {LastEventId: 2}} {
+		update, err := watch(req).Recv()
+		require.NoError(t, err)
+		assert.True(t, update.GetResync(), "a resume point from another epoch cannot be replayed")
+		assert.Equal(t, epoch, update.GetEpoch())
+		assert.Nil(t, update.GetLtp())
+	}
+
+	_, err := watch(&ltpv1.WatchLTPRequest{ResumeAfter: epoch + "-x"}).Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_Health(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	_, conn := startServer(t, service)
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: ltpv1.LTPService_ServiceDesc.ServiceName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+}
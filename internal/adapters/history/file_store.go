@@ -0,0 +1,339 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const (
+	defaultCompactInterval = time.Minute
+
+	// Prices waiting to be written; when the disk falls this far behind, new ones are kept in memory only.
+	writeQueueSize = 4096
+)
+
+type Options struct {
+	// Retention drops prices older than this; zero keeps them forever.
+	Retention time.Duration
+	// MaxEntries caps how many prices are kept per pair; zero means no cap.
+	MaxEntries int
+	// CompactInterval is how often retention is applied and the log rewritten; zero means every minute.
+	CompactInterval time.Duration
+}
+
+// FileStore keeps prices in memory and appends each one as a JSON line to a log file so they survive restarts.
+// Writes and compaction happen on a background goroutine, never on the caller's path. An empty path keeps
+// history in memory only.
+type FileStore struct {
+	mu     sync.RWMutex
+	path   string
+	opts   Options
+	series map[domain.Pair][]domain.LTP
+	closed bool
+
+	// file and its writer belong to the background goroutine once NewFileStore returns.
+	file    *os.File
+	w       *bufio.Writer
+	pending chan domain.LTP
+	dropped int
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewFileStore(path string, opts Options) (*FileStore, error) {
+	if opts.CompactInterval <= 0 {
+		opts.CompactInterval = defaultCompactInterval
+	}
+	s := &FileStore{
+		path:    path,
+		opts:    opts,
+		series:  make(map[domain.Pair][]domain.LTP),
+		pending: make(chan domain.LTP, writeQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("create history directory: %w", err)
+		}
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		s.prune(time.Now())
+		if err := s.rewrite(s.snapshot()); err != nil {
+			return nil, err
+		}
+	}
+
+	go s.run()
+	return s, nil
+}
+
+// Append records ltp in memory at once and queues it for the log file.
+func (s *FileStore) Append(ctx context.Context, ltp domain.LTP) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.insert(ltp)
+	if s.path == "" || s.closed {
+		return nil
+	}
+	select {
+	case s.pending <- ltp:
+	default:
+		s.dropped++
+	}
+	return nil
+}
+
+func (s *FileStore) Range(ctx context.Context, pair domain.Pair, from, to time.Time) ([]domain.LTP, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.series[pair]
+	start := sort.Search(len(series), func(i int) bool { return !series[i].Timestamp.Before(from) })
+	end := sort.Search(len(series), func(i int) bool { return series[i].Timestamp.After(to) })
+	if start >= end {
+		return []domain.LTP{}, nil
+	}
+	return append([]domain.LTP(nil), series[start:end]...), nil
+}
+
+func (s *FileStore) At(ctx context.Context, pair domain.Pair, t time.Time) (domain.LTP, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	series := s.series[pair]
+	i := sort.Search(len(series), func(i int) bool { return series[i].Timestamp.After(t) })
+	if i == 0 {
+		return domain.LTP{}, false, nil
+	}
+	return series[i-1], true, nil
+}
+
+// Close writes out whatever is still queued and closes the log.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// run writes queued prices, flushing whenever the queue runs dry, and compacts on a timer.
+func (s *FileStore) run() {
+	defer close(s.done)
+	t := time.NewTicker(s.opts.CompactInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case ltp := <-s.pending:
+			s.write(ltp)
+			if len(s.pending) == 0 {
+				s.flush()
+			}
+		case <-t.C:
+			s.compact()
+		case <-s.stop:
+			for {
+				select {
+				case ltp := <-s.pending:
+					s.write(ltp)
+				default:
+					s.flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *FileStore) write(ltp domain.LTP) {
+	if s.w == nil {
+		return
+	}
+	data, err := json.Marshal(ltp)
+	if err != nil {
+		return
+	}
+	if _, err := s.w.Write(append(data, '\n')); err != nil {
+		log.GetInstance().Warn("Failed to append price history: %v", err)
+	}
+}
+
+func (s *FileStore) flush() {
+	if s.w == nil {
+		return
+	}
+	if err := s.w.Flush(); err != nil {
+		log.GetInstance().Warn("Failed to flush price history: %v", err)
+	}
+}
+
+// compact applies retention and, if anything was dropped, rewrites the log from memory.
+func (s *FileStore) compact() {
+	s.mu.Lock()
+	if s.dropped > 0 {
+		log.GetInstance().Warn("History writer fell behind, %d prices were not persisted", s.dropped)
+		s.dropped = 0
+	}
+	if !s.prune(time.Now()) || s.path == "" {
+		s.mu.Unlock()
+		return
+	}
+	// Everything still queued is already in memory, so the rewrite covers it.
+	for len(s.pending) > 0 {
+		<-s.pending
+	}
+	series := s.snapshot()
+	s.mu.Unlock()
+
+	if err := s.rewrite(series); err != nil {
+		log.GetInstance().Error("Failed to compact price history: %v", err)
+	}
+}
+
+// snapshot copies the retained prices so the log can be rewritten without holding the lock.
+func (s *FileStore) snapshot() [][]domain.LTP {
+	out := make([][]domain.LTP, 0, len(s.series))
+	for _, series := range s.series {
+		out = append(out, append([]domain.LTP(nil), series...))
+	}
+	return out
+}
+
+// insert keeps each pair's series ordered by timestamp; prices almost always arrive in order so this is an append.
+func (s *FileStore) insert(ltp domain.LTP) {
+	series := s.series[ltp.Pair]
+	i := sort.Search(len(series), func(i int) bool { return series[i].Timestamp.After(ltp.Timestamp) })
+	series = append(series, domain.LTP{})
+	copy(series[i+1:], series[i:])
+	series[i] = ltp
+	s.series[ltp.Pair] = series
+}
+
+// prune applies the retention limits and reports whether anything was dropped.
+func (s *FileStore) prune(now time.Time) bool {
+	dropped := false
+	for pair, series := range s.series {
+		start := 0
+		if s.opts.Retention > 0 {
+			cutoff := now.Add(-s.opts.Retention)
+			start = sort.Search(len(series), func(i int) bool { return !series[i].Timestamp.Before(cutoff) })
+		}
+		if s.opts.MaxEntries > 0 && len(series)-start > s.opts.MaxEntries {
+			start = len(series) - s.opts.MaxEntries
+		}
+		if start == 0 {
+			continue
+		}
+		dropped = true
+		if start == len(series) {
+			delete(s.series, pair)
+			continue
+		}
+		s.series[pair] = append([]domain.LTP(nil), series[start:]...)
+	}
+	return dropped
+}
+
+func (s *FileStore) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open history: %w", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.GetInstance().Error("Error closing history file: %v", err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	skipped := 0
+	for scanner.Scan() {
+		var ltp domain.LTP
+		if err := json.Unmarshal(scanner.Bytes(), &ltp); err != nil {
+			// A crash mid-write can leave a torn last line; keep everything else.
+			skipped++
+			continue
+		}
+		s.insert(ltp)
+	}
+	if skipped > 0 {
+		log.GetInstance().Warn("Skipped %d unreadable history entries in %s", skipped, s.path)
+	}
+	return scanner.Err()
+}
+
+// rewrite replaces the log with series and reopens it for appending.
+func (s *FileStore) rewrite(series [][]domain.LTP) error {
+	s.flush()
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			log.GetInstance().Error("Error closing history file: %v", err)
+		}
+		s.file, s.w = nil, nil
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("compact history: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, ltps := range series {
+		for _, ltp := range ltps {
+			if err := enc.Encode(ltp); err != nil {
+				_ = f.Close()
+				return fmt.Errorf("compact history: %w", err)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("compact history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("compact history: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("compact history: %w", err)
+	}
+
+	s.file, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		s.w = nil
+		return fmt.Errorf("open history: %w", err)
+	}
+	s.w = bufio.NewWriter(s.file)
+	return nil
+}
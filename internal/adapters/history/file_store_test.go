@@ -0,0 +1,93 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func price(pair domain.Pair, amount int64, ts time.Time) domain.LTP {
+	return domain.LTP{Pair: pair, Amount: decimal.NewFromInt(amount), Timestamp: ts.UTC()}
+}
+
+func TestFileStore_RangeAndAt(t *testing.T) {
+	store, err := NewFileStore("", Options{})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	base := time.Date(2024, 5, 1, 14, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 100, base)))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 102, base.Add(2*time.Minute))))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 101, base.Add(time.Minute))))
+	require.NoError(t, store.Append(ctx, price("BTC/USD", 200, base)))
+
+	ltps, err := store.Range(ctx, "BTC/EUR", base, base.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, ltps, 2)
+	assert.Equal(t, "101", ltps[1].Amount.String())
+
+	ltp, ok, err := store.At(ctx, "BTC/EUR", base.Add(3*time.Minute))
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "102", ltp.Amount.String())
+
+	_, ok, err = store.At(ctx, "BTC/EUR", base.Add(-time.Second))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestFileStore_PersistsAndAppliesRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.log")
+	ctx := context.Background()
+	now := time.Now()
+
+	store, err := NewFileStore(path, Options{Retention: time.Hour, MaxEntries: 2})
+	require.NoError(t, err)
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 99, now.Add(-2*time.Hour))))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 100, now.Add(-3*time.Minute))))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 101, now.Add(-2*time.Minute))))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 102, now.Add(-time.Minute))))
+	require.NoError(t, store.Close())
+
+	reopened, err := NewFileStore(path, Options{Retention: time.Hour, MaxEntries: 2})
+	require.NoError(t, err)
+	defer func() { _ = reopened.Close() }()
+
+	ltps, err := reopened.Range(ctx, "BTC/EUR", now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, ltps, 2)
+	assert.Equal(t, "101", ltps[0].Amount.String())
+	assert.Equal(t, "102", ltps[1].Amount.String())
+}
+
+func TestFileStore_CompactsInBackground(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.log")
+	ctx := context.Background()
+	now := time.Now()
+
+	store, err := NewFileStore(path, Options{Retention: time.Hour, CompactInterval: 20 * time.Millisecond})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 99, now.Add(-2*time.Hour))))
+	require.NoError(t, store.Append(ctx, price("BTC/EUR", 100, now)))
+
+	// The expired price is still served until the next compaction drops it from memory and disk.
+	require.Eventually(t, func() bool {
+		ltps, _ := store.Range(ctx, "BTC/EUR", now.Add(-24*time.Hour), now)
+		return len(ltps) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(path)
+		return err == nil && strings.Count(string(data), "\n") == 1 && strings.Contains(string(data), `"100"`)
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -1,9 +1,15 @@
 package httpapi
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/internal/application"
@@ -12,14 +18,28 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shopspring/decimal"
 )
 
 type Handler struct {
-	service *application.LTPService
+	service          *application.LTPService
+	maxSubscriptions int
+	supported        map[domain.Pair]bool
+
+	connMu       sync.Mutex
+	conns        map[*wsConn]struct{}
+	connWG       sync.WaitGroup
+	shuttingDown bool
+	done         chan struct{}
 }
 
 func NewHandler(s *application.LTPService) *Handler {
-	return &Handler{service: s}
+	return &Handler{
+		service:          s,
+		maxSubscriptions: defaultMaxSubscriptions,
+		conns:            make(map[*wsConn]struct{}),
+		done:             make(chan struct{}),
+	}
 }
 
 func (h *Handler) Router() http.Handler {
@@ -28,6 +48,15 @@ func (h *Handler) Router() http.Handler {
 	r.Use(metricsMiddleware)
 
 	r.Get("/api/v1/ltp", h.getLTP)
+	r.Get("/api/v1/ltp/history", h.getHistory)
+	r.Get("/api/v1/ltp/at", h.getLTPAt)
+	r.Get("/api/v1/ltp/stream", h.streamLTP)
+	r.Get("/api/v1/ticker", h.getTicker)
+	r.Get("/api/v1/orderbook", h.getOrderBook)
+	r.Get("/api/v1/ohlc", h.getOHLC)
+	r.Get("/api/v1/trades", h.getTrades)
+	r.Get("/api/v1/convert", h.convert)
+	r.Get("/ws", h.serveWS)
 	r.Get("/health", h.health)
 	r.Get("/ready", h.ready)
 	r.Get("/healthz", h.healthz)
@@ -46,6 +75,9 @@ type successResponse struct {
 	Meta map[string]interface{} `json:"meta"`
 }
 
+// Non-standard status used by nginx and others for requests the client abandoned.
+const statusClientClosedRequest = 499
+
 type statusResponse struct {
 	Status string `json:"status"`
 }
@@ -85,11 +117,20 @@ func (h *Handler) getLTP(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query().Get("pairs")
 	pairs := parsePairsParam(q)
 
-	var ltps []domain.LTP
-	if len(pairs) == 0 {
-		ltps = h.service.GetAllLTPs()
-	} else {
-		ltps = h.service.GetLTPs(pairs)
+	source := strings.TrimSpace(r.URL.Query().Get("source"))
+
+	ltps, err := h.service.GetLTPsFrom(r.Context(), source, pairs)
+	if errors.Is(err, application.ErrUnknownSource) {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "UNKNOWN_SOURCE",
+		})
+		return
+	}
+
+	if err := r.Context().Err(); err != nil {
+		respondContextError(w, err)
+		return
 	}
 
 	if len(ltps) == 0 {
@@ -100,14 +141,468 @@ func (h *Handler) getLTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	meta := map[string]interface{}{
+		"count": len(ltps),
+	}
+	if source != "" {
+		meta["source"] = source
+	}
+
 	respondJSON(w, http.StatusOK, successResponse{
 		Data: ltps,
+		Meta: meta,
+	})
+}
+
+func (h *Handler) getTicker(w http.ResponseWriter, r *http.Request) {
+	pairs := parsePairsParam(r.URL.Query().Get("pairs"))
+
+	tickers, err := h.service.GetTickers(r.Context(), pairs)
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		respondContextError(w, ctxErr)
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "TICKER_UNAVAILABLE",
+		})
+		return
+	}
+
+	if len(tickers) == 0 {
+		respondJSON(w, http.StatusNotFound, errorResponse{
+			Error: "Requested pairs not found",
+			Code:  "NOT_FOUND",
+		})
+		return
+	}
+
+	// Pairs that failed are reported alongside the rest; only a request where every pair failed is an error.
+	failed := 0
+	for _, t := range tickers {
+		if t.Error != "" {
+			failed++
+		}
+	}
+	if failed == len(tickers) {
+		respondUpstreamError(w, tickers[0].Error)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: tickers,
+		Meta: map[string]interface{}{
+			"count": len(tickers),
+		},
+	})
+}
+
+func (h *Handler) getOrderBook(w http.ResponseWriter, r *http.Request) {
+	pair := domain.Pair(strings.TrimSpace(r.URL.Query().Get("pair")))
+	if pair == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "pair is required",
+			Code:  "MISSING_PAIR",
+		})
+		return
+	}
+
+	depth := 0
+	if d := strings.TrimSpace(r.URL.Query().Get("depth")); d != "" {
+		n, err := strconv.Atoi(d)
+		if err != nil || n <= 0 {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "depth must be a positive integer",
+				Code:  "INVALID_DEPTH",
+			})
+			return
+		}
+		depth = n
+	}
+
+	book, err := h.service.GetOrderBook(r.Context(), pair, depth)
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		respondContextError(w, ctxErr)
+		return
+	}
+	switch {
+	case errors.Is(err, application.ErrInvalidDepth):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_DEPTH",
+		})
+		return
+	case err != nil:
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "ORDER_BOOK_UNAVAILABLE",
+		})
+		return
+	case book.Error != "":
+		respondUpstreamError(w, book.Error)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: book,
+		Meta: map[string]interface{}{
+			"bids": len(book.Bids),
+			"asks": len(book.Asks),
+		},
+	})
+}
+
+func (h *Handler) getOHLC(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair := domain.Pair(strings.TrimSpace(query.Get("pair")))
+	if pair == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "pair is required",
+			Code:  "MISSING_PAIR",
+		})
+		return
+	}
+
+	interval := 1
+	if v := strings.TrimSpace(query.Get("interval")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "interval must be a number of minutes",
+				Code:  "INVALID_INTERVAL",
+			})
+			return
+		}
+		interval = n
+	}
+
+	var since time.Time
+	if v := strings.TrimSpace(query.Get("since")); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "since must be a unix timestamp in seconds",
+				Code:  "INVALID_SINCE",
+			})
+			return
+		}
+		since = time.Unix(n, 0)
+	}
+
+	series, err := h.service.GetCandles(r.Context(), pair, interval, since)
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		respondContextError(w, ctxErr)
+		return
+	}
+	switch {
+	case errors.Is(err, application.ErrInvalidInterval):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_INTERVAL",
+		})
+		return
+	case err != nil:
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "OHLC_UNAVAILABLE",
+		})
+		return
+	case series.Error != "":
+		respondUpstreamError(w, series.Error)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: series,
 		Meta: map[string]interface{}{
+			"count":    len(series.Candles),
+			"interval": series.Interval,
+		},
+	})
+}
+
+func (h *Handler) getTrades(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair := domain.Pair(strings.TrimSpace(query.Get("pair")))
+	if pair == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "pair is required",
+			Code:  "MISSING_PAIR",
+		})
+		return
+	}
+
+	limit := 0
+	if v := strings.TrimSpace(query.Get("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "limit must be a positive integer",
+				Code:  "INVALID_LIMIT",
+			})
+			return
+		}
+		limit = n
+	}
+
+	batch, err := h.service.GetTrades(r.Context(), pair, limit, strings.TrimSpace(query.Get("cursor")))
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		respondContextError(w, ctxErr)
+		return
+	}
+	switch {
+	case errors.Is(err, application.ErrInvalidLimit):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_LIMIT",
+		})
+		return
+	case errors.Is(err, application.ErrInvalidCursor):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_CURSOR",
+		})
+		return
+	case err != nil:
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "TRADES_UNAVAILABLE",
+		})
+		return
+	case batch.Error != "":
+		respondUpstreamError(w, batch.Error)
+		return
+	}
+
+	meta := map[string]interface{}{
+		"pair":    batch.Pair,
+		"count":   len(batch.Trades),
+		"hasMore": batch.Cursor != "",
+	}
+	if batch.Cursor != "" {
+		meta["nextCursor"] = batch.Cursor
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: batch.Trades,
+		Meta: meta,
+	})
+}
+
+func (h *Handler) convert(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	from := strings.TrimSpace(query.Get("from"))
+	to := strings.TrimSpace(query.Get("to"))
+	if from == "" || to == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "from and to are required",
+			Code:  "MISSING_CURRENCY",
+		})
+		return
+	}
+
+	amount := decimal.NewFromInt(1)
+	if v := strings.TrimSpace(query.Get("amount")); v != "" {
+		d, err := decimal.NewFromString(v)
+		if err != nil || d.IsNegative() {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "amount must be a non-negative decimal number",
+				Code:  "INVALID_AMOUNT",
+			})
+			return
+		}
+		amount = d
+	}
+
+	conv, err := h.service.Convert(r.Context(), from, to, amount)
+	if ctxErr := r.Context().Err(); ctxErr != nil {
+		respondContextError(w, ctxErr)
+		return
+	}
+	switch {
+	case errors.Is(err, application.ErrInvalidCurrency):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_CURRENCY",
+		})
+		return
+	case errors.Is(err, application.ErrInvalidAmount):
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: err.Error(),
+			Code:  "INVALID_AMOUNT",
+		})
+		return
+	case err != nil:
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "CONVERT_UNAVAILABLE",
+		})
+		return
+	case conv.Error != "":
+		respondUpstreamError(w, conv.Error)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: conv,
+		Meta: map[string]interface{}{
+			"pair":     conv.Pair,
+			"inverted": conv.Inverted,
+		},
+	})
+}
+
+// parseTime accepts RFC 3339 or unix seconds.
+func parseTime(v string) (time.Time, error) {
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(n, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+func (h *Handler) getHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair := domain.Pair(strings.TrimSpace(query.Get("pair")))
+	if pair == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "pair is required",
+			Code:  "MISSING_PAIR",
+		})
+		return
+	}
+
+	to := time.Now().UTC()
+	if v := strings.TrimSpace(query.Get("to")); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "to must be RFC 3339 or a unix timestamp",
+				Code:  "INVALID_TIME",
+			})
+			return
+		}
+		to = t
+	}
+	from := to.Add(-24 * time.Hour)
+	if v := strings.TrimSpace(query.Get("from")); v != "" {
+		t, err := parseTime(v)
+		if err != nil {
+			respondJSON(w, http.StatusBadRequest, errorResponse{
+				Error: "from must be RFC 3339 or a unix timestamp",
+				Code:  "INVALID_TIME",
+			})
+			return
+		}
+		from = t
+	}
+	if from.After(to) {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "from must not be after to",
+			Code:  "INVALID_TIME",
+		})
+		return
+	}
+
+	ltps, err := h.service.GetHistory(r.Context(), pair, from, to)
+	if err != nil {
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "HISTORY_UNAVAILABLE",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: ltps,
+		Meta: map[string]interface{}{
+			"pair":  pair,
 			"count": len(ltps),
+			"from":  from,
+			"to":    to,
 		},
 	})
 }
 
+func (h *Handler) getLTPAt(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pair := domain.Pair(strings.TrimSpace(query.Get("pair")))
+	if pair == "" {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "pair is required",
+			Code:  "MISSING_PAIR",
+		})
+		return
+	}
+	at, err := parseTime(strings.TrimSpace(query.Get("time")))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "time must be RFC 3339 or a unix timestamp",
+			Code:  "INVALID_TIME",
+		})
+		return
+	}
+
+	ltp, ok, err := h.service.GetLTPAt(r.Context(), pair, at)
+	if err != nil {
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: err.Error(),
+			Code:  "HISTORY_UNAVAILABLE",
+		})
+		return
+	}
+	if !ok {
+		respondJSON(w, http.StatusNotFound, errorResponse{
+			Error: "No price recorded for the pair at or before the requested time",
+			Code:  "NOT_FOUND",
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, successResponse{
+		Data: ltp,
+		Meta: map[string]interface{}{
+			"time": at,
+		},
+	})
+}
+
+// respondUpstreamError answers with the error an upstream fetch returned in its result: an unlisted
+// pair is 404, a rate limit 429 and any other failure 502.
+func respondUpstreamError(w http.ResponseWriter, errMsg string) {
+	switch {
+	case strings.HasPrefix(errMsg, domain.UnsupportedPairPrefix):
+		respondJSON(w, http.StatusNotFound, errorResponse{
+			Error: errMsg,
+			Code:  "NOT_FOUND",
+		})
+	case strings.HasPrefix(errMsg, domain.RateLimitedPrefix):
+		respondJSON(w, http.StatusTooManyRequests, errorResponse{
+			Error: errMsg,
+			Code:  "RATE_LIMITED",
+		})
+	default:
+		respondJSON(w, http.StatusBadGateway, errorResponse{
+			Error: errMsg,
+			Code:  "UPSTREAM_ERROR",
+		})
+	}
+}
+
+func respondContextError(w http.ResponseWriter, err error) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		respondJSON(w, http.StatusGatewayTimeout, errorResponse{
+			Error: "Request timed out",
+			Code:  "TIMEOUT",
+		})
+		return
+	}
+	respondJSON(w, statusClientClosedRequest, errorResponse{
+		Error: "Request canceled",
+		Code:  "REQUEST_CANCELED",
+	})
+}
+
 func (h *Handler) health(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, statusResponse{Status: "ok"})
 }
@@ -177,9 +672,25 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Hijack lets WebSocket upgrades through the metrics middleware.
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	rw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer for flushing and deadlines.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 type healthzResponse struct {
-	Status   string            `json:"status"`
-	Services map[string]string `json:"services,omitempty"`
+	Status    string                       `json:"status"`
+	Services  map[string]string            `json:"services,omitempty"`
+	Providers []application.ProviderHealth `json:"providers,omitempty"`
 }
 
 func IncrementCacheHit() {
@@ -207,19 +718,23 @@ func (h *Handler) healthz(w http.ResponseWriter, r *http.Request) {
 		servicesStatus["kraken"] = "unreachable"
 	}
 
+	providers := h.service.ProviderHealth()
+
 	status := "ok"
 	if !redisReachable || !krakenReachable {
 		status = "degraded"
 		respondJSON(w, http.StatusServiceUnavailable, healthzResponse{
-			Status:   status,
-			Services: servicesStatus,
+			Status:    status,
+			Services:  servicesStatus,
+			Providers: providers,
 		})
 		return
 	}
 
 	respondJSON(w, http.StatusOK, healthzResponse{
-		Status:   status,
-		Services: servicesStatus,
+		Status:    status,
+		Services:  servicesStatus,
+		Providers: providers,
 	})
 }
 
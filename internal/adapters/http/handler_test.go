@@ -0,0 +1,328 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService() *application.LTPService {
+	return application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+}
+
+func serve(service *application.LTPService, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	NewHandler(service).Router().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+	return rec
+}
+
+// get runs a request through the router and returns the status with the error code, if the body has one.
+func get(t *testing.T, service *application.LTPService, url string) (int, string) {
+	t.Helper()
+	rec := serve(service, url)
+	var body errorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body.Code
+}
+
+// getData expects a 200 and decodes the response's data into out.
+func getData(t *testing.T, service *application.LTPService, url string, out interface{}) {
+	t.Helper()
+	rec := serve(service, url)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	body := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+}
+
+type statusCase struct {
+	url    string
+	status int
+	code   string
+}
+
+func checkStatuses(t *testing.T, service *application.LTPService, cases []statusCase) {
+	t.Helper()
+	for _, tt := range cases {
+		t.Run(tt.url, func(t *testing.T) {
+			status, code := get(t, service, tt.url)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+// The upstream stubs fail BTC/XYZ as unlisted, ETH/USD as rate limited and SOL/USD with a server error.
+var (
+	unlistedPair    = domain.Pair("BTC/XYZ")
+	rateLimitedPair = domain.Pair("ETH/USD")
+	failingPair     = domain.Pair("SOL/USD")
+)
+
+func upstreamError(pair domain.Pair) string {
+	switch pair {
+	case unlistedPair:
+		return domain.UnsupportedPairPrefix + ": " + string(pair)
+	case rateLimitedPair:
+		return domain.RateLimitedPrefix
+	}
+	return "kraken error: status 503"
+}
+
+// upstreamCases are the error mappings every market-data endpoint shares; url ends where the pair goes.
+func upstreamCases(url string) []statusCase {
+	return []statusCase{
+		{url + string(unlistedPair), http.StatusNotFound, "NOT_FOUND"},
+		{url + string(rateLimitedPair), http.StatusTooManyRequests, "RATE_LIMITED"},
+		{url + string(failingPair), http.StatusBadGateway, "UPSTREAM_ERROR"},
+	}
+}
+
+type stubTickers map[domain.Pair]domain.Ticker
+
+func (s stubTickers) FetchTickers(_ context.Context, pairs []domain.Pair) []domain.Ticker {
+	out := make([]domain.Ticker, len(pairs))
+	for i, p := range pairs {
+		t, ok := s[p]
+		if !ok {
+			t = domain.Ticker{Pair: p, Error: upstreamError(p), Timestamp: time.Now()}
+		}
+		out[i] = t
+	}
+	return out
+}
+
+func TestGetTicker(t *testing.T) {
+	status, code := get(t, newTestService(), "/api/v1/ticker?pairs=BTC/USD")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "TICKER_UNAVAILABLE", code)
+
+	service := newTestService()
+	service.SetTickerProvider(stubTickers{
+		"BTC/USD": {Pair: "BTC/USD", Last: decimal.NewFromInt(50000), Timestamp: time.Now()},
+	})
+
+	var tickers []domain.Ticker
+	getData(t, service, "/api/v1/ticker?pairs=BTC/USD,BTC/XYZ", &tickers)
+	require.Len(t, tickers, 2, "failed pairs are reported alongside the rest")
+	assert.Equal(t, "50000", tickers[0].Last.String())
+	assert.NotEmpty(t, tickers[1].Error)
+
+	checkStatuses(t, service, upstreamCases("/api/v1/ticker?pairs="))
+}
+
+type stubBooks map[domain.Pair]domain.OrderBook
+
+func (s stubBooks) FetchOrderBook(_ context.Context, pair domain.Pair, _ int) domain.OrderBook {
+	if book, ok := s[pair]; ok {
+		return book
+	}
+	return domain.OrderBook{Pair: pair, Error: upstreamError(pair), Timestamp: time.Now()}
+}
+
+func TestGetOrderBook(t *testing.T) {
+	levels := make([]domain.PriceLevel, 10)
+	for i := range levels {
+		levels[i] = domain.PriceLevel{Price: decimal.NewFromInt(int64(50000 - i)), Volume: decimal.NewFromInt(1)}
+	}
+	service := newTestService()
+	service.SetOrderBookProvider(stubBooks{
+		"BTC/USD": {Pair: "BTC/USD", Bids: levels, Timestamp: time.Now()},
+	}, application.OrderBookOptions{MaxDepth: 10})
+
+	var book domain.OrderBook
+	getData(t, service, "/api/v1/orderbook?pair=BTC/USD&depth=3", &book)
+	require.Len(t, book.Bids, 3)
+	assert.Equal(t, "50000", book.Bids[0].Price.String())
+
+	checkStatuses(t, service, append([]statusCase{
+		{"/api/v1/orderbook?depth=5", http.StatusBadRequest, "MISSING_PAIR"},
+		{"/api/v1/orderbook?pair=BTC/USD&depth=abc", http.StatusBadRequest, "INVALID_DEPTH"},
+		{"/api/v1/orderbook?pair=BTC/USD&depth=0", http.StatusBadRequest, "INVALID_DEPTH"},
+		{"/api/v1/orderbook?pair=BTC/USD&depth=11", http.StatusBadRequest, "INVALID_DEPTH"},
+	}, upstreamCases("/api/v1/orderbook?pair=")...))
+
+	status, code := get(t, newTestService(), "/api/v1/orderbook?pair=BTC/USD")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "ORDER_BOOK_UNAVAILABLE", code)
+}
+
+type stubCandles map[domain.Pair]domain.CandleSeries
+
+func (s stubCandles) FetchCandles(_ context.Context, pair domain.Pair, interval int) domain.CandleSeries {
+	series, ok := s[pair]
+	if !ok {
+		series = domain.CandleSeries{Pair: pair, Error: upstreamError(pair), Timestamp: time.Now()}
+	}
+	series.Interval = interval
+	return series
+}
+
+func (s stubCandles) Intervals() []int { return []int{1, 60} }
+
+func TestGetOHLC(t *testing.T) {
+	service := newTestService()
+	service.SetCandleProvider(stubCandles{
+		"BTC/USD": {Pair: "BTC/USD", Candles: []domain.Candle{{Time: time.Unix(1700000000, 0).UTC(), Close: decimal.NewFromInt(50000)}}, Timestamp: time.Now()},
+	})
+
+	var series domain.CandleSeries
+	getData(t, service, "/api/v1/ohlc?pair=BTC/USD&interval=60", &series)
+	assert.Equal(t, 60, series.Interval)
+	require.Len(t, series.Candles, 1)
+	assert.Equal(t, "50000", series.Candles[0].Close.String())
+
+	checkStatuses(t, service, append([]statusCase{
+		{"/api/v1/ohlc?interval=60", http.StatusBadRequest, "MISSING_PAIR"},
+		{"/api/v1/ohlc?pair=BTC/USD&interval=1h", http.StatusBadRequest, "INVALID_INTERVAL"},
+		{"/api/v1/ohlc?pair=BTC/USD&interval=7", http.StatusBadRequest, "INVALID_INTERVAL"},
+		{"/api/v1/ohlc?pair=BTC/USD&since=-1", http.StatusBadRequest, "INVALID_SINCE"},
+	}, upstreamCases("/api/v1/ohlc?pair=")...))
+
+	status, code := get(t, newTestService(), "/api/v1/ohlc?pair=BTC/USD")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "OHLC_UNAVAILABLE", code)
+}
+
+type stubTrades map[domain.Pair]domain.TradeBatch
+
+func (s stubTrades) FetchTrades(_ context.Context, pair domain.Pair, _ string) domain.TradeBatch {
+	if batch, ok := s[pair]; ok {
+		return batch
+	}
+	return domain.TradeBatch{Pair: pair, Error: upstreamError(pair), Timestamp: time.Now()}
+}
+
+func TestGetTrades(t *testing.T) {
+	trades := make([]domain.Trade, 3)
+	for i := range trades {
+		trades[i] = domain.Trade{ID: int64(i + 1), Price: decimal.NewFromInt(int64(50000 + i)), Volume: decimal.NewFromInt(1), Time: time.Now()}
+	}
+	service := newTestService()
+	service.SetTradeProvider(stubTrades{
+		"BTC/USD": {Pair: "BTC/USD", Trades: trades, Cursor: "3", Timestamp: time.Now()},
+	}, application.TradesOptions{Buffer: 100, MaxLimit: 50})
+
+	var page []domain.Trade
+	getData(t, service, "/api/v1/trades?pair=BTC/USD&limit=2", &page)
+	require.Len(t, page, 2)
+	assert.Equal(t, []int64{3, 2}, []int64{page[0].ID, page[1].ID}, "newest first")
+
+	getData(t, service, "/api/v1/trades?pair=BTC/USD&limit=2&cursor=2", &page)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(1), page[0].ID)
+
+	checkStatuses(t, service, append([]statusCase{
+		{"/api/v1/trades?limit=10", http.StatusBadRequest, "MISSING_PAIR"},
+		{"/api/v1/trades?pair=BTC/USD&limit=ten", http.StatusBadRequest, "INVALID_LIMIT"},
+		{"/api/v1/trades?pair=BTC/USD&limit=-1", http.StatusBadRequest, "INVALID_LIMIT"},
+		{"/api/v1/trades?pair=BTC/USD&limit=51", http.StatusBadRequest, "INVALID_LIMIT"},
+		{"/api/v1/trades?pair=BTC/USD&cursor=abc", http.StatusBadRequest, "INVALID_CURSOR"},
+	}, upstreamCases("/api/v1/trades?pair=")...))
+
+	status, code := get(t, newTestService(), "/api/v1/trades?pair=BTC/USD")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "TRADES_UNAVAILABLE", code)
+}
+
+// stubHistory holds one pair's prices, oldest first.
+type stubHistory []domain.LTP
+
+func (s stubHistory) Append(context.Context, domain.LTP) error { return nil }
+
+func (s stubHistory) Range(_ context.Context, pair domain.Pair, from, to time.Time) ([]domain.LTP, error) {
+	var out []domain.LTP
+	for _, ltp := range s {
+		if ltp.Pair == pair && !ltp.Timestamp.Before(from) && !ltp.Timestamp.After(to) {
+			out = append(out, ltp)
+		}
+	}
+	return out, nil
+}
+
+func (s stubHistory) At(_ context.Context, pair domain.Pair, t time.Time) (domain.LTP, bool, error) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i].Pair == pair && !s[i].Timestamp.After(t) {
+			return s[i], true, nil
+		}
+	}
+	return domain.LTP{}, false, nil
+}
+
+func TestGetHistory(t *testing.T) {
+	service := newTestService()
+	service.SetHistoryStore(stubHistory{
+		{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Unix(1700000000, 0).UTC()},
+	})
+
+	tests := []struct {
+		url    string
+		status int
+		code   string
+	}{
+		{"/api/v1/ltp/history?pair=BTC/USD&from=1699990000&to=1700010000", http.StatusOK, ""},
+		{"/api/v1/ltp/history?from=1699990000", http.StatusBadRequest, "MISSING_PAIR"},
+		{"/api/v1/ltp/history?pair=BTC/USD&from=yesterday", http.StatusBadRequest, "INVALID_TIME"},
+		{"/api/v1/ltp/history?pair=BTC/USD&to=tomorrow", http.StatusBadRequest, "INVALID_TIME"},
+		{"/api/v1/ltp/history?pair=BTC/USD&from=1700010000&to=1699990000", http.StatusBadRequest, "INVALID_TIME"},
+		{"/api/v1/ltp/at?pair=BTC/USD&time=2023-11-14T23:00:00Z", http.StatusOK, ""},
+		{"/api/v1/ltp/at?time=1700000000", http.StatusBadRequest, "MISSING_PAIR"},
+		{"/api/v1/ltp/at?pair=BTC/USD", http.StatusBadRequest, "INVALID_TIME"},
+		{"/api/v1/ltp/at?pair=BTC/USD&time=1699990000", http.StatusNotFound, "NOT_FOUND"},
+		{"/api/v1/ltp/at?pair=ETH/USD&time=1700000000", http.StatusNotFound, "NOT_FOUND"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			status, code := get(t, service, tt.url)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+
+	status, code := get(t, newTestService(), "/api/v1/ltp/at?pair=BTC/USD&time=1700000000")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, "HISTORY_UNAVAILABLE", code)
+}
+
+func TestConvert(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now()})
+	for _, pair := range []domain.Pair{rateLimitedPair, failingPair} {
+		provider.SetResponse(pair, domain.LTP{Pair: pair, Error: upstreamError(pair), Timestamp: time.Now()})
+	}
+	provider.SetResponse("XYZ/USD", domain.LTP{Pair: "XYZ/USD", Error: upstreamError(unlistedPair), Timestamp: time.Now()})
+	provider.SetResponse("USD/XYZ", domain.LTP{Pair: "USD/XYZ", Error: upstreamError(unlistedPair), Timestamp: time.Now()})
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+
+	var conv domain.Conversion
+	getData(t, service, "/api/v1/convert?from=BTC&to=USD&amount=0.5", &conv)
+	assert.Equal(t, "25000", conv.Result.String())
+	assert.Equal(t, domain.Pair("BTC/USD"), conv.Pair)
+	assert.False(t, conv.Inverted)
+
+	conv = domain.Conversion{}
+	getData(t, service, "/api/v1/convert?from=USD&to=BTC&amount=25000", &conv)
+	assert.Equal(t, "0.5", conv.Result.String())
+	assert.True(t, conv.Inverted)
+
+	checkStatuses(t, service, []statusCase{
+		{"/api/v1/convert?from=BTC", http.StatusBadRequest, "MISSING_CURRENCY"},
+		{"/api/v1/convert?from=BTC/USD&to=EUR", http.StatusBadRequest, "INVALID_CURRENCY"},
+		{"/api/v1/convert?from=BTC&to=USD&amount=abc", http.StatusBadRequest, "INVALID_AMOUNT"},
+		{"/api/v1/convert?from=BTC&to=USD&amount=-1", http.StatusBadRequest, "INVALID_AMOUNT"},
+		{"/api/v1/convert?from=XYZ&to=USD", http.StatusNotFound, "NOT_FOUND"},
+		{"/api/v1/convert?from=ETH&to=USD", http.StatusTooManyRequests, "RATE_LIMITED"},
+		{"/api/v1/convert?from=SOL&to=USD", http.StatusBadGateway, "UPSTREAM_ERROR"},
+	})
+}
@@ -0,0 +1,101 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+)
+
+// sseHeartbeat is a variable so tests need not wait for the production interval.
+var sseHeartbeat = 15 * time.Second
+
+// streamLTP pushes price updates as Server-Sent Events until the client goes away or falls too far behind.
+func (h *Handler) streamLTP(w http.ResponseWriter, r *http.Request) {
+	pairs := parsePairsParam(r.URL.Query().Get("pairs"))
+
+	lastEventID, reset, err := h.service.ParseEventID(strings.TrimSpace(r.Header.Get("Last-Event-ID")))
+	if err != nil {
+		respondJSON(w, http.StatusBadRequest, errorResponse{
+			Error: "Last-Event-ID must be an event id sent by this stream",
+			Code:  "INVALID_EVENT_ID",
+		})
+		return
+	}
+
+	if h.closing() {
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: "Server is shutting down",
+			Code:  "SHUTTING_DOWN",
+		})
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's write timeout is meant for ordinary requests; a stream stays open until the client leaves.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.GetInstance().Debug("Cannot clear write deadline for SSE stream: %v", err)
+	}
+
+	sub := h.service.Subscribe(pairs, lastEventID)
+	defer h.service.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.GetInstance().Warn("SSE streaming not supported by response writer: %v", err)
+		return
+	}
+
+	epoch := h.service.UpdatesEpoch()
+	if reset || sub.Reset {
+		// The ID was issued before a restart, or the events after it are no longer buffered, so the
+		// replay would have a gap; tell the client to resync.
+		if _, err := fmt.Fprintf(w, "event: reset\ndata: {\"epoch\":%q}\n\n", epoch); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case ev, ok := <-sub.Events:
+			if !ok {
+				if sub.Dropped() {
+					log.GetInstance().Info("Closed SSE stream for slow client %s", r.RemoteAddr)
+				}
+				return
+			}
+			data, err := json.Marshal(ev.LTP)
+			if err != nil {
+				log.GetInstance().Error("Cannot encode SSE event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: ltp\ndata: %s\n\n", h.service.EventID(ev.ID), data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package httpapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseEvent struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+type sseStream struct {
+	resp   *http.Response
+	events chan sseEvent
+}
+
+func openSSE(t *testing.T, handler *Handler, query, lastEventID string) *sseStream {
+	t.Helper()
+	server := httptest.NewServer(handler.Router())
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/ltp/stream"+query, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	stream := &sseStream{resp: resp, events: make(chan sseEvent, 16)}
+	if resp.StatusCode != http.StatusOK {
+		return stream
+	}
+	go func() {
+		defer close(stream.events)
+		scanner := bufio.NewScanner(resp.Body)
+		var ev sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				stream.events <- ev
+				ev = sseEvent{}
+			case strings.HasPrefix(line, ":"):
+				ev.comment = strings.TrimSpace(line[1:])
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return stream
+}
+
+func (s *sseStream) next(t *testing.T) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-s.events:
+		require.True(t, ok, "stream closed")
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no SSE event received")
+		return sseEvent{}
+	}
+}
+
+func ingest(service *application.LTPService, pair domain.Pair, amount int64) {
+	service.Ingest(context.Background(), domain.LTP{Pair: pair, Amount: decimal.NewFromInt(amount), Timestamp: time.Now()})
+}
+
+func eventPair(t *testing.T, ev sseEvent) domain.Pair {
+	t.Helper()
+	var ltp domain.LTP
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &ltp))
+	return ltp.Pair
+}
+
+func TestStreamLTP_FiltersPairs(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	stream := openSSE(t, NewHandler(service), "?pairs=BTC/USD", "")
+
+	ingest(service, "BTC/EUR", 45000)
+	ingest(service, "BTC/USD", 50000)
+
+	ev := stream.next(t)
+	assert.Equal(t, "ltp", ev.event)
+	assert.Equal(t, domain.Pair("BTC/USD"), eventPair(t, ev))
+	assert.Equal(t, service.UpdatesEpoch()+"-2", ev.id)
+}
+
+func TestStreamLTP_SendsHeartbeats(t *testing.T) {
+	defer func(d time.Duration) { sseHeartbeat = d }(sseHeartbeat)
+	sseHeartbeat = 20 * time.Millisecond
+
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	stream := openSSE(t, NewHandler(service), "", "")
+
+	assert.Equal(t, "heartbeat", stream.next(t).comment)
+}
+
+func TestStreamLTP_ReplaysAfterLastEventID(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	for i := int64(1); i <= 3; i++ {
+		ingest(service, "BTC/USD", 50000+i)
+	}
+
+	stream := openSSE(t, NewHandler(service), "", service.UpdatesEpoch()+"-1")
+
+	epoch := service.UpdatesEpoch()
+	assert.Equal(t, epoch+"-2", stream.next(t).id)
+	assert.Equal(t, epoch+"-3", stream.next(t).id)
+}
+
+func TestStreamLTP_ResetsForeignEventID(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	ingest(service, "BTC/USD", 50000)
+
+	// A bare number is what the previous process, or an older build, handed out.
+	stream := openSSE(t, NewHandler(service), "", "1")
+	ev := stream.next(t)
+	assert.Equal(t, "reset", ev.event)
+	assert.Contains(t, ev.data, service.UpdatesEpoch())
+
+	ingest(service, "BTC/USD", 50001)
+	assert.Equal(t, service.UpdatesEpoch()+"-2", stream.next(t).id, "nothing from before the reset is replayed")
+}
+
+func TestStreamLTP_ResetsWhenEventIDFellOutOfBuffer(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	// The hub keeps the last 256 events, so event 1 is gone once 258 have been published.
+	for i := int64(1); i <= 258; i++ {
+		ingest(service, "BTC/USD", 50000+i)
+	}
+
+	stream := openSSE(t, NewHandler(service), "", service.UpdatesEpoch()+"-1")
+	ev := stream.next(t)
+	assert.Equal(t, "reset", ev.event)
+
+	ingest(service, "BTC/USD", 60000)
+	assert.Equal(t, service.UpdatesEpoch()+"-259", stream.next(t).id, "nothing is replayed after a reset")
+}
+
+func TestStreamLTP_RejectsMalformedEventID(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	stream := openSSE(t, NewHandler(service), "", "not-an-id")
+	assert.Equal(t, http.StatusBadRequest, stream.resp.StatusCode)
+}
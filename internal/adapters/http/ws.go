@@ -0,0 +1,407 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteTimeout    = 5 * time.Second
+	wsPongTimeout     = 60 * time.Second
+	wsPingInterval    = 30 * time.Second
+	wsOutboundBuffer  = 64
+	wsMaxMessageBytes = 4096
+
+	defaultMaxSubscriptions = 20
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Prices are public and the widgets are embedded on other origins.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsRequest is a client command: subscribe, unsubscribe, ping or snapshot.
+type wsRequest struct {
+	Type  string        `json:"type"`
+	Pairs []domain.Pair `json:"pairs,omitempty"`
+}
+
+type wsResponse struct {
+	Type  string        `json:"type"`
+	ID    uint64        `json:"id,omitempty"`
+	Pairs []domain.Pair `json:"pairs,omitempty"`
+	Data  interface{}   `json:"data,omitempty"`
+	Error string        `json:"error,omitempty"`
+	Code  string        `json:"code,omitempty"`
+}
+
+type wsConn struct {
+	conn      *websocket.Conn
+	service   *application.LTPService
+	maxPairs  int
+	supported map[domain.Pair]bool
+
+	mu    sync.Mutex
+	pairs map[domain.Pair]bool
+
+	// One snapshot runs at a time, off the read goroutine so pings and unsubscribes are not held up.
+	snapshotting atomic.Bool
+	snapshots    sync.WaitGroup
+
+	out       chan wsResponse
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// SetSupportedPairs limits WebSocket subscriptions to pairs; until it is called any pair is accepted.
+func (h *Handler) SetSupportedPairs(pairs []domain.Pair) {
+	h.supported = make(map[domain.Pair]bool, len(pairs))
+	for _, p := range pairs {
+		h.supported[p] = true
+	}
+}
+
+// SetMaxSubscriptions caps how many pairs a single WebSocket connection may subscribe to.
+func (h *Handler) SetMaxSubscriptions(n int) {
+	if n > 0 {
+		h.maxSubscriptions = n
+	}
+}
+
+func (h *Handler) serveWS(w http.ResponseWriter, r *http.Request) {
+	if h.closing() {
+		respondJSON(w, http.StatusServiceUnavailable, errorResponse{
+			Error: "Server is shutting down",
+			Code:  "SHUTTING_DOWN",
+		})
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.GetInstance().Debug("WebSocket upgrade failed: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &wsConn{
+		conn:      conn,
+		service:   h.service,
+		maxPairs:  h.maxSubscriptions,
+		supported: h.supported,
+		pairs:     make(map[domain.Pair]bool),
+		out:       make(chan wsResponse, wsOutboundBuffer),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	if !h.track(c) {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+		return
+	}
+	defer h.untrack(c)
+
+	sub := h.service.Subscribe(nil, 0)
+	defer h.service.Unsubscribe(sub)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		c.writeLoop()
+	}()
+	go func() {
+		defer wg.Done()
+		c.forward(sub)
+	}()
+
+	c.readLoop()
+	c.cancel()
+	c.snapshots.Wait()
+	wg.Wait()
+}
+
+func (c *wsConn) readLoop() {
+	c.conn.SetReadLimit(wsMaxMessageBytes)
+	_ = c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		var req wsRequest
+		if err := c.conn.ReadJSON(&req); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.GetInstance().Debug("WebSocket read error: %v", err)
+			}
+			return
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+		c.handle(req)
+	}
+}
+
+func (c *wsConn) handle(req wsRequest) {
+	switch req.Type {
+	case "ping":
+		c.send(wsResponse{Type: "pong"})
+	case "subscribe":
+		if len(req.Pairs) == 0 {
+			c.sendError("MISSING_PAIRS", "subscribe requires pairs")
+			return
+		}
+		if unknown := c.unsupported(req.Pairs); len(unknown) > 0 {
+			c.sendError("UNSUPPORTED_PAIR", fmt.Sprintf("unsupported pairs: %s", strings.Join(unknown, ", ")))
+			return
+		}
+		pairs, err := c.subscribe(req.Pairs)
+		if err != nil {
+			c.sendError("SUBSCRIPTION_LIMIT", err.Error())
+			return
+		}
+		c.send(wsResponse{Type: "subscribed", Pairs: pairs})
+	case "unsubscribe":
+		c.send(wsResponse{Type: "unsubscribed", Pairs: c.unsubscribe(req.Pairs)})
+	case "snapshot":
+		pairs := req.Pairs
+		if len(pairs) == 0 {
+			pairs = c.subscribed()
+		}
+		if len(pairs) == 0 {
+			c.sendError("MISSING_PAIRS", "snapshot requires pairs or an active subscription")
+			return
+		}
+		if len(pairs) > c.maxPairs {
+			c.sendError("SUBSCRIPTION_LIMIT", fmt.Sprintf("at most %d pairs per request", c.maxPairs))
+			return
+		}
+		if !c.snapshotting.CompareAndSwap(false, true) {
+			c.sendError("SNAPSHOT_IN_PROGRESS", "wait for the previous snapshot before requesting another")
+			return
+		}
+		c.snapshots.Add(1)
+		go func() {
+			defer c.snapshots.Done()
+			defer c.snapshotting.Store(false)
+			ltps := c.service.GetLTPs(c.ctx, pairs)
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.send(wsResponse{Type: "snapshot", Data: ltps})
+		}()
+	default:
+		c.sendError("UNKNOWN_TYPE", fmt.Sprintf("unknown message type %q", req.Type))
+	}
+}
+
+// unsupported lists the requested pairs outside the supported set, if one was configured.
+func (c *wsConn) unsupported(pairs []domain.Pair) []string {
+	if len(c.supported) == 0 {
+		return nil
+	}
+	var out []string
+	for _, p := range pairs {
+		if p != "" && !c.supported[p] {
+			out = append(out, string(p))
+		}
+	}
+	return out
+}
+
+func (c *wsConn) subscribe(pairs []domain.Pair) ([]domain.Pair, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	added := 0
+	for _, p := range pairs {
+		if p != "" && !c.pairs[p] {
+			added++
+		}
+	}
+	if len(c.pairs)+added > c.maxPairs {
+		return nil, fmt.Errorf("at most %d subscriptions per connection", c.maxPairs)
+	}
+	for _, p := range pairs {
+		if p != "" {
+			c.pairs[p] = true
+		}
+	}
+	return c.sortedPairs(), nil
+}
+
+// unsubscribe removes pairs, or every pair when none are given, and returns what remains subscribed.
+func (c *wsConn) unsubscribe(pairs []domain.Pair) []domain.Pair {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(pairs) == 0 {
+		c.pairs = make(map[domain.Pair]bool)
+	}
+	for _, p := range pairs {
+		delete(c.pairs, p)
+	}
+	return c.sortedPairs()
+}
+
+func (c *wsConn) subscribed() []domain.Pair {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sortedPairs()
+}
+
+func (c *wsConn) sortedPairs() []domain.Pair {
+	out := make([]domain.Pair, 0, len(c.pairs))
+	for p := range c.pairs {
+		out = append(out, p)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+func (c *wsConn) forward(sub *application.Subscription) {
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				c.close(websocket.ClosePolicyViolation, "client too slow")
+				return
+			}
+			c.mu.Lock()
+			wanted := c.pairs[ev.LTP.Pair]
+			c.mu.Unlock()
+			if wanted {
+				c.send(wsResponse{Type: "update", ID: ev.ID, Data: ev.LTP})
+			}
+		}
+	}
+}
+
+// send queues a message; a client whose queue is full is disconnected rather than allowed to slow down fan-out.
+func (c *wsConn) send(msg wsResponse) {
+	select {
+	case c.out <- msg:
+	case <-c.ctx.Done():
+	default:
+		log.GetInstance().Warn("Dropping WebSocket client %s that fell behind", c.conn.RemoteAddr())
+		c.close(websocket.ClosePolicyViolation, "client too slow")
+	}
+}
+
+func (c *wsConn) sendError(code, msg string) {
+	c.send(wsResponse{Type: "error", Code: code, Error: msg})
+}
+
+func (c *wsConn) writeLoop() {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case msg := <-c.out:
+			data, err := json.Marshal(msg)
+			if err != nil {
+				log.GetInstance().Error("Cannot encode WebSocket message: %v", err)
+				continue
+			}
+			_ = c.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		}
+	}
+}
+
+// close sends a close frame when it can and tears the connection down; safe to call more than once.
+func (c *wsConn) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.cancel()
+		if code != websocket.CloseAbnormalClosure {
+			msg := websocket.FormatCloseMessage(code, reason)
+			_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+		}
+		if err := c.conn.Close(); err != nil {
+			log.GetInstance().Debug("WebSocket close error: %v", err)
+		}
+	})
+}
+
+func (h *Handler) track(c *wsConn) bool {
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+	if h.shuttingDown {
+		return false
+	}
+	h.conns[c] = struct{}{}
+	h.connWG.Add(1)
+	return true
+}
+
+func (h *Handler) untrack(c *wsConn) {
+	c.close(websocket.CloseNormalClosure, "")
+	h.connMu.Lock()
+	delete(h.conns, c)
+	h.connMu.Unlock()
+	h.connWG.Done()
+}
+
+func (h *Handler) closing() bool {
+	h.connMu.Lock()
+	defer h.connMu.Unlock()
+	return h.shuttingDown
+}
+
+// Shutdown ends every WebSocket and SSE stream and waits, until ctx expires, for their handlers to return.
+// http.Server.Shutdown does not do this itself: hijacked connections are not tracked and streams never go idle.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	h.connMu.Lock()
+	if !h.shuttingDown {
+		h.shuttingDown = true
+		close(h.done)
+	}
+	conns := make([]*wsConn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.connMu.Unlock()
+
+	for _, c := range conns {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		h.connWG.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,115 @@
+package httpapi
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/application"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dialWS(t *testing.T, handler *Handler) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(handler.Router())
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readWS(t *testing.T, conn *websocket.Conn) wsResponse {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg wsResponse
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestWebSocket_SubscribeAndReceiveUpdates(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+	handler := NewHandler(service)
+	handler.SetMaxSubscriptions(2)
+	conn := dialWS(t, handler)
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "ping"}))
+	assert.Equal(t, "pong", readWS(t, conn).Type)
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "subscribe", Pairs: []domain.Pair{"BTC/USD"}}))
+	msg := readWS(t, conn)
+	assert.Equal(t, "subscribed", msg.Type)
+	assert.Equal(t, []domain.Pair{"BTC/USD"}, msg.Pairs)
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "subscribe", Pairs: []domain.Pair{"BTC/EUR", "BTC/CHF"}}))
+	msg = readWS(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "SUBSCRIPTION_LIMIT", msg.Code)
+
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now()})
+	provider.SetResponse("BTC/EUR", domain.LTP{Pair: "BTC/EUR", Amount: decimal.NewFromInt(45000), Timestamp: time.Now()})
+	service.RefreshPairs(context.Background(), []domain.Pair{"BTC/EUR", "BTC/USD"})
+
+	msg = readWS(t, conn)
+	assert.Equal(t, "update", msg.Type)
+	assert.Equal(t, "BTC/USD", msg.Data.(map[string]interface{})["pair"])
+}
+
+func TestWebSocket_ShutdownClosesConnections(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	handler := NewHandler(service)
+	conn := dialWS(t, handler)
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "ping"}))
+	readWS(t, conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, handler.Shutdown(ctx))
+
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+}
+
+func TestWebSocket_RejectsUnsupportedPairs(t *testing.T) {
+	service := application.NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	handler := NewHandler(service)
+	handler.SetSupportedPairs([]domain.Pair{"BTC/USD"})
+	conn := dialWS(t, handler)
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "subscribe", Pairs: []domain.Pair{"BTC/USD", "FOO/BAR"}}))
+	msg := readWS(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "UNSUPPORTED_PAIR", msg.Code)
+	assert.Contains(t, msg.Error, "FOO/BAR")
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "subscribe", Pairs: []domain.Pair{"BTC/USD"}}))
+	assert.Equal(t, []domain.Pair{"BTC/USD"}, readWS(t, conn).Pairs)
+}
+
+func TestWebSocket_SnapshotDoesNotBlockReads(t *testing.T) {
+	provider := mocks.NewMockMarketDataProvider()
+	provider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(50000), Timestamp: time.Now()})
+	provider.SetDelay("BTC/USD", 300*time.Millisecond)
+	service := application.NewLTPService(mocks.NewMockCache(), provider, time.Minute)
+	conn := dialWS(t, NewHandler(service))
+
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "snapshot", Pairs: []domain.Pair{"BTC/USD"}}))
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "snapshot", Pairs: []domain.Pair{"BTC/USD"}}))
+	require.NoError(t, conn.WriteJSON(wsRequest{Type: "ping"}))
+
+	msg := readWS(t, conn)
+	assert.Equal(t, "error", msg.Type)
+	assert.Equal(t, "SNAPSHOT_IN_PROGRESS", msg.Code)
+	assert.Equal(t, "pong", readWS(t, conn).Type, "the ping is answered while the snapshot is still fetching")
+	assert.Equal(t, "snapshot", readWS(t, conn).Type)
+}
@@ -0,0 +1,311 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const (
+	defaultPairsRefreshInterval = time.Hour
+	pairsRetryInterval          = 30 * time.Second
+)
+
+// Kraken still uses its own codes for a handful of assets.
+var assetAliases = map[string]string{
+	"BTC":  "XBT",
+	"DOGE": "XDG",
+}
+
+// Markets are reported under the common codes rather than Kraken's aliases.
+var displayAliases = map[string]string{
+	"XBT": "BTC",
+	"XDG": "DOGE",
+}
+
+// Kraken's legacy X/Z-prefixed codes. Other four-letter assets starting with X or Z, such as ZEUS, are real codes.
+var legacyAssets = map[string]string{
+	"XXBT": "XBT",
+	"XETH": "ETH",
+	"XETC": "ETC",
+	"XLTC": "LTC",
+	"XXRP": "XRP",
+	"XXLM": "XLM",
+	"XXMR": "XMR",
+	"XZEC": "ZEC",
+	"XREP": "REP",
+	"XMLN": "MLN",
+	"XXDG": "XDG",
+	"ZUSD": "USD",
+	"ZEUR": "EUR",
+	"ZGBP": "GBP",
+	"ZCAD": "CAD",
+	"ZJPY": "JPY",
+	"ZAUD": "AUD",
+	"ZCHF": "CHF",
+}
+
+// Used until the AssetPairs catalogue has been loaded at least once.
+var fallbackSymbols = map[string]string{
+	"XBT/USD": "XXBTZUSD",
+	"XBT/CHF": "XXBTZCHF",
+	"XBT/EUR": "XXBTZEUR",
+}
+
+type UnsupportedPairError struct {
+	Pair domain.Pair
+}
+
+func (e *UnsupportedPairError) Error() string {
+	return fmt.Sprintf("pair not supported: %s", e.Pair)
+}
+
+type krakenAssetPairsResp struct {
+	Error  []string                         `json:"error"`
+	Result map[string]krakenAssetPairsEntry `json:"result"`
+}
+
+type krakenAssetPairsEntry struct {
+	Altname string `json:"altname"`
+	Wsname  string `json:"wsname"`
+	Base    string `json:"base"`
+	Quote   string `json:"quote"`
+}
+
+type pairCatalog struct {
+	mu       sync.RWMutex
+	symbols  map[string]string
+	markets  []domain.Pair
+	loadedAt time.Time
+	refresh  time.Duration
+	stop     context.CancelFunc
+}
+
+func newPairCatalog() *pairCatalog {
+	markets := make([]domain.Pair, 0, len(fallbackSymbols))
+	for pair := range fallbackSymbols {
+		base, quote, _ := strings.Cut(pair, "/")
+		markets = append(markets, marketPair(base, quote))
+	}
+	return &pairCatalog{
+		symbols: fallbackSymbols,
+		markets: markets,
+		refresh: defaultPairsRefreshInterval,
+	}
+}
+
+func (p *pairCatalog) lookup(pair string) (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	symbol, ok := p.symbols[normalizePair(pair)]
+	return symbol, ok
+}
+
+func (p *pairCatalog) replace(symbols map[string]string, markets []domain.Pair) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.symbols = symbols
+	p.markets = markets
+	p.loadedAt = time.Now()
+}
+
+// SetPairsRefreshInterval controls how often the AssetPairs catalogue is reloaded.
+func (c *Client) SetPairsRefreshInterval(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	c.catalog.mu.Lock()
+	defer c.catalog.mu.Unlock()
+	c.catalog.refresh = d
+}
+
+// LoadAssetPairs downloads the AssetPairs catalogue and replaces the known symbols.
+func (c *Client) LoadAssetPairs(ctx context.Context) error {
+	_, err, _ := c.catalogSF.Do("asset-pairs", func() (interface{}, error) {
+		if err := c.wait(ctx); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/0/public/AssetPairs", c.baseURL), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				log.GetInstance().Error("Error close HTTP request: %v", err)
+			}
+		}()
+
+		if err := checkStatus(resp); err != nil {
+			return nil, err
+		}
+		var parsed krakenAssetPairsResp
+		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+			return nil, err
+		}
+		if len(parsed.Error) > 0 {
+			return nil, fmt.Errorf("kraken error: %v", parsed.Error)
+		}
+		if len(parsed.Result) == 0 {
+			return nil, fmt.Errorf("kraken returned an empty AssetPairs catalogue")
+		}
+
+		c.catalog.replace(buildSymbolIndex(parsed.Result), buildMarkets(parsed.Result))
+		log.GetInstance().Info("Loaded %d Kraken asset pairs", len(parsed.Result))
+		return nil, nil
+	})
+	return err
+}
+
+// StartPairsRefresh reloads the AssetPairs catalogue in the background every refresh interval, retrying
+// sooner after a failed load, so lookups never wait on the download.
+func (c *Client) StartPairsRefresh() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.catalog.mu.Lock()
+	if c.catalog.stop != nil {
+		c.catalog.mu.Unlock()
+		cancel()
+		return
+	}
+	c.catalog.stop = cancel
+	c.catalog.mu.Unlock()
+
+	go func() {
+		wait := c.pairsRefreshInterval()
+		for {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+			wait = c.pairsRefreshInterval()
+			if err := c.LoadAssetPairs(ctx); err != nil && ctx.Err() == nil {
+				log.GetInstance().Warn("Failed to refresh Kraken asset pairs: %v", err)
+				wait = pairsRetryInterval
+			}
+		}
+	}()
+}
+
+// StopPairsRefresh ends the background refresh; StartPairsRefresh may start it again afterwards.
+func (c *Client) StopPairsRefresh() {
+	c.catalog.mu.Lock()
+	defer c.catalog.mu.Unlock()
+	if c.catalog.stop != nil {
+		c.catalog.stop()
+		c.catalog.stop = nil
+	}
+}
+
+// pairsRefreshInterval is the refresh interval, or the retry interval while nothing has loaded yet.
+func (c *Client) pairsRefreshInterval() time.Duration {
+	c.catalog.mu.RLock()
+	defer c.catalog.mu.RUnlock()
+	if c.catalog.loadedAt.IsZero() {
+		return pairsRetryInterval
+	}
+	return c.catalog.refresh
+}
+
+// IsSupported reports whether the pair can be mapped to a Kraken REST symbol.
+func (c *Client) IsSupported(pair domain.Pair) bool {
+	_, err := c.ResolveSymbol(pair)
+	return err == nil
+}
+
+// ResolveSymbol maps a human pair such as ETH/USD or XBT/EUR to its Kraken REST symbol.
+// The catalogue is only read here; StartPairsRefresh keeps it current.
+func (c *Client) ResolveSymbol(pair domain.Pair) (string, error) {
+	symbol, ok := c.catalog.lookup(string(pair))
+	if !ok {
+		return "", &UnsupportedPairError{Pair: pair}
+	}
+	return symbol, nil
+}
+
+// Markets lists every pair Kraken trades directly, as BASE/QUOTE with common asset codes such as BTC.
+func (c *Client) Markets() []domain.Pair {
+	c.catalog.mu.RLock()
+	defer c.catalog.mu.RUnlock()
+	return append([]domain.Pair(nil), c.catalog.markets...)
+}
+
+func buildMarkets(entries map[string]krakenAssetPairsEntry) []domain.Pair {
+	markets := make([]domain.Pair, 0, len(entries))
+	for _, entry := range entries {
+		base, quote := stripLegacyPrefix(entry.Base), stripLegacyPrefix(entry.Quote)
+		if base == "" || quote == "" {
+			var ok bool
+			if base, quote, ok = strings.Cut(entry.Wsname, "/"); !ok {
+				continue
+			}
+		}
+		markets = append(markets, marketPair(base, quote))
+	}
+	return markets
+}
+
+func marketPair(base, quote string) domain.Pair {
+	if alias, ok := displayAliases[base]; ok {
+		base = alias
+	}
+	if alias, ok := displayAliases[quote]; ok {
+		quote = alias
+	}
+	return domain.Pair(base + "/" + quote)
+}
+
+func buildSymbolIndex(entries map[string]krakenAssetPairsEntry) map[string]string {
+	symbols := make(map[string]string, len(entries)*4)
+	for symbol, entry := range entries {
+		keys := []string{symbol, entry.Altname}
+		if entry.Wsname != "" {
+			keys = append(keys, entry.Wsname)
+		}
+		if entry.Base != "" && entry.Quote != "" {
+			keys = append(keys, stripLegacyPrefix(entry.Base)+"/"+stripLegacyPrefix(entry.Quote))
+		}
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			symbols[normalizePair(key)] = symbol
+		}
+	}
+	return symbols
+}
+
+func normalizePair(pair string) string {
+	pair = strings.ToUpper(strings.TrimSpace(pair))
+	base, quote, found := strings.Cut(pair, "/")
+	if !found {
+		return pair
+	}
+	return normalizeAsset(base) + "/" + normalizeAsset(quote)
+}
+
+func normalizeAsset(asset string) string {
+	asset = strings.TrimSpace(asset)
+	if alias, ok := assetAliases[asset]; ok {
+		return alias
+	}
+	return stripLegacyPrefix(asset)
+}
+
+// stripLegacyPrefix turns a legacy code such as XXBT or ZUSD into XBT or USD and leaves any other asset alone.
+func stripLegacyPrefix(asset string) string {
+	if code, ok := legacyAssets[asset]; ok {
+		return code
+	}
+	return asset
+}
@@ -1,26 +1,37 @@
 package kraken
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
 	"github.com/shopspring/decimal"
 	"github.com/cenkalti/backoff/v4"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
+const Source = "kraken"
+
 type Client struct {
-	baseURL string
-	http    *http.Client
+	baseURL   string
+	http      *http.Client
+	catalog   *pairCatalog
+	catalogSF singleflight.Group
+	limiter   *rate.Limiter
+	maxWait   time.Duration
 }
 
 func NewClient(baseURL string, timeout uint) *Client {
 	return &Client{
 		baseURL: baseURL,
 		http:    &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		catalog: newPairCatalog(),
 	}
 }
 
@@ -34,22 +45,95 @@ type krakenTickerEntry struct {
 	B []string `json:"b"`
 	C []string `json:"c"`
 	V []string `json:"v"`
+	P []string `json:"p"`
+	L []string `json:"l"`
+	H []string `json:"h"`
+	O string   `json:"o"`
 }
 
-func (c *Client) Fetch(pair domain.Pair) domain.LTP {
-	symbolPair, err := convertCurrencyPairToKrakenSymbol(string(pair))
+func (c *Client) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	symbolPair, err := c.ResolveSymbol(pair)
+	if err != nil {
+		return errorLTP(pair, unsupportedMessage(pair))
+	}
+
+	result, err := c.fetchTicker(ctx, []string{symbolPair})
 	if err != nil {
-		return domain.LTP{
-			Pair:      pair,
-			Error:     fmt.Sprintf("unsupported pair: %s", err.Error()),
-			Timestamp: time.Now().UTC(),
+		return c.failedLTP(ctx, pair, err)
+	}
+	return toLTP(pair, symbolPair, result)
+}
+
+// FetchMany asks the Ticker endpoint for every pair in one request; results follow the order of pairs.
+func (c *Client) FetchMany(ctx context.Context, pairs []domain.Pair) []domain.LTP {
+	out := make([]domain.LTP, len(pairs))
+	symbols, query := c.resolveAll(ctx, pairs)
+	for i, pair := range pairs {
+		if symbols[i] == "" {
+			out[i] = errorLTP(pair, unsupportedMessage(pair))
 		}
 	}
+	if len(query) == 0 {
+		return out
+	}
+
+	result, err := c.fetchTicker(ctx, query)
+	for i, pair := range pairs {
+		if symbols[i] == "" {
+			continue
+		}
+		if err != nil {
+			out[i] = c.failedLTP(ctx, pair, err)
+			continue
+		}
+		out[i] = toLTP(pair, symbols[i], result)
+	}
+	return out
+}
 
+// resolveAll maps pairs to Kraken symbols, leaving unsupported ones empty, and returns the distinct symbols to query.
+func (c *Client) resolveAll(ctx context.Context, pairs []domain.Pair) (symbols []string, query []string) {
+	symbols = make([]string, len(pairs))
+	seen := make(map[string]bool)
+	for i, pair := range pairs {
+		symbolPair, err := c.ResolveSymbol(pair)
+		if err != nil {
+			continue
+		}
+		symbols[i] = symbolPair
+		if !seen[symbolPair] {
+			seen[symbolPair] = true
+			query = append(query, symbolPair)
+		}
+	}
+	return symbols, query
+}
+
+func unsupportedMessage(pair domain.Pair) string {
+	return fmt.Sprintf("%s: %s", domain.UnsupportedPairPrefix, (&UnsupportedPairError{Pair: pair}).Error())
+}
+
+func (c *Client) fetchTicker(ctx context.Context, symbols []string) (map[string]krakenTickerEntry, error) {
 	var parsed krakenTickerResp
+	err := c.getPublic(ctx, fmt.Sprintf("Ticker?pair=%s", strings.Join(symbols, ",")), &parsed, func() []string { return parsed.Error })
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Result, nil
+}
+
+// getPublic calls a public REST endpoint under the rate limit, retrying transient failures with exponential backoff.
+func (c *Client) getPublic(ctx context.Context, path string, out interface{}, apiErrors func() []string) error {
 	op := func() error {
-		url := fmt.Sprintf("%s/0/public/Ticker?pair=%s", c.baseURL, symbolPair)
-		resp, err := c.http.Get(url)
+		if err := c.wait(ctx); err != nil {
+			return backoff.Permanent(err)
+		}
+		url := fmt.Sprintf("%s/0/public/%s", c.baseURL, path)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+		resp, err := c.http.Do(req)
 		if err != nil {
 			return err
 		}
@@ -59,70 +143,85 @@ func (c *Client) Fetch(pair domain.Pair) domain.LTP {
 			}
 		}()
 
-		if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		if err := checkStatus(resp); err != nil {
+			return err
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
 			return err
 		}
-		if len(parsed.Error) > 0 {
-			return fmt.Errorf("kraken error: %v", parsed.Error)
+		if errs := apiErrors(); len(errs) > 0 {
+			return fmt.Errorf("kraken error: %v", errs)
 		}
 		return nil
 	}
 
 	// Retry with exponential backoff (max 3 attempts)
-	expBackoff := backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3)
-	if err := backoff.Retry(op, expBackoff); err != nil {
-		log.GetInstance().Warn("Failed to fetch pair %s: %v", pair, err)
-		return domain.LTP{
-			Pair:      pair,
-			Error:     err.Error(),
-			Timestamp: time.Now().UTC(),
-		}
+	expBackoff := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), 3), ctx)
+	return backoff.Retry(op, expBackoff)
+}
+
+// checkStatus rejects a non-200 reply before its body is decoded. A 429 is a rate limit and is not
+// retried; 5xx replies are retried and anything else fails at once.
+func checkStatus(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return backoff.Permanent(ErrUpstreamRateLimited)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("kraken error: status %d", resp.StatusCode)
+	default:
+		return backoff.Permanent(fmt.Errorf("kraken error: status %d", resp.StatusCode))
+	}
+}
+
+func (c *Client) failedLTP(ctx context.Context, pair domain.Pair, err error) domain.LTP {
+	if ctx.Err() != nil {
+		log.GetInstance().Debug("Fetch for pair %s canceled: %v", pair, ctx.Err())
+		return errorLTP(pair, fmt.Sprintf("%v: %v", domain.ErrCanceled, ctx.Err()))
+	}
+	if isRateLimited(err) {
+		log.GetInstance().Warn("Kraken rate limit reached fetching pair %s", pair)
+		return errorLTP(pair, err.Error())
 	}
+	log.GetInstance().Warn("Failed to fetch pair %s: %v", pair, err)
+	return errorLTP(pair, err.Error())
+}
 
-	entry, exists := parsed.Result[symbolPair]
+func toLTP(pair domain.Pair, symbolPair string, result map[string]krakenTickerEntry) domain.LTP {
+	entry, exists := result[symbolPair]
 	if !exists {
-		return domain.LTP{
-			Pair:      pair,
-			Error:     fmt.Sprintf("Pair %s not found in response", symbolPair),
-			Timestamp: time.Now().UTC(),
-		}
+		return errorLTP(pair, fmt.Sprintf("Pair %s not found in response", symbolPair))
 	}
 
 	if len(entry.C) == 0 {
-		return domain.LTP{
-			Pair:      pair,
-			Error:     "No last trade price data available",
-			Timestamp: time.Now().UTC(),
-		}
+		return errorLTP(pair, "No last trade price data available")
 	}
 
 	price, err := decimal.NewFromString(entry.C[0])
 	if err != nil {
-		return domain.LTP{
-			Pair:      pair,
-			Error:     fmt.Sprintf("Invalid price format: %v", err),
-			Timestamp: time.Now().UTC(),
-		}
+		return errorLTP(pair, fmt.Sprintf("Invalid price format: %v", err))
 	}
 
-	return domain.LTP{
+	ltp := domain.LTP{
 		Pair:      pair,
 		Amount:    price,
 		Timestamp: time.Now().UTC(),
+		Source:    Source,
 	}
-}
-
-func convertCurrencyPairToKrakenSymbol(pair string) (string, error) {
-	krakenSymbols := map[string]string{
-		"BTC/USD": "XXBTZUSD",
-		"BTC/CHF": "XXBTZCHF",
-		"BTC/EUR": "XXBTZEUR",
+	if len(entry.V) > 1 {
+		if volume, err := decimal.NewFromString(entry.V[1]); err == nil {
+			ltp.Volume = &volume
+		}
 	}
+	return ltp
+}
 
-	symbol, exists := krakenSymbols[pair]
-	if !exists {
-		return "", fmt.Errorf("pair not supported: %s", pair)
+func errorLTP(pair domain.Pair, msg string) domain.LTP {
+	return domain.LTP{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
 	}
-
-	return symbol, nil
 }
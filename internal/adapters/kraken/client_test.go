@@ -0,0 +1,303 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const assetPairsBody = `{"error":[],"result":{
+	"XXBTZUSD":{"altname":"XBTUSD","wsname":"XBT/USD","base":"XXBT","quote":"ZUSD"},
+	"XETHZEUR":{"altname":"ETHEUR","wsname":"ETH/EUR","base":"XETH","quote":"ZEUR"},
+	"SOLUSD":{"altname":"SOLUSD","wsname":"SOL/USD","base":"SOL","quote":"ZUSD"},
+	"ZEUSUSD":{"altname":"ZEUSUSD","wsname":"ZEUS/USD","base":"ZEUS","quote":"ZUSD"}
+}}`
+
+func newKrakenServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ResolveSymbol(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{"/0/public/AssetPairs": assetPairsBody})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	tests := []struct {
+		pair   domain.Pair
+		symbol string
+	}{
+		{"BTC/USD", "XXBTZUSD"},
+		{"XBT/USD", "XXBTZUSD"},
+		{"xbtusd", "XXBTZUSD"},
+		{"ETH/EUR", "XETHZEUR"},
+		{"XETH/ZEUR", "XETHZEUR"},
+		{"SOL/USD", "SOLUSD"},
+		{"ZEUS/USD", "ZEUSUSD"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.pair), func(t *testing.T) {
+			symbol, err := client.ResolveSymbol(tt.pair)
+			require.NoError(t, err)
+			assert.Equal(t, tt.symbol, symbol)
+		})
+	}
+}
+
+func TestClient_Markets(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{"/0/public/AssetPairs": assetPairsBody})
+	client := NewClient(server.URL, 2)
+	assert.ElementsMatch(t, []domain.Pair{"BTC/USD", "BTC/EUR", "BTC/CHF"}, client.Markets())
+
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+	assert.ElementsMatch(t, []domain.Pair{"BTC/USD", "ETH/EUR", "SOL/USD", "ZEUS/USD"}, client.Markets())
+}
+
+func TestClient_PairsRefreshRunsInBackground(t *testing.T) {
+	var loads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loads.Add(1)
+		_, _ = w.Write([]byte(assetPairsBody))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+	client.SetPairsRefreshInterval(20 * time.Millisecond)
+
+	// Lookups never download the catalogue themselves.
+	_, err := client.ResolveSymbol("ETH/EUR")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), loads.Load())
+
+	client.StartPairsRefresh()
+	require.Eventually(t, func() bool { return loads.Load() >= 3 }, time.Second, 5*time.Millisecond)
+
+	// A stopped refresher can be started again.
+	client.StopPairsRefresh()
+	stopped := loads.Load()
+	client.StartPairsRefresh()
+	defer client.StopPairsRefresh()
+	require.Eventually(t, func() bool { return loads.Load() >= stopped+2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestClient_ResolveSymbol_Unsupported(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{"/0/public/AssetPairs": assetPairsBody})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	_, err := client.ResolveSymbol("BTC/CHF")
+	var unsupported *UnsupportedPairError
+	require.True(t, errors.As(err, &unsupported))
+	assert.Equal(t, domain.Pair("BTC/CHF"), unsupported.Pair)
+	assert.False(t, client.IsSupported("DOGE/JPY"))
+}
+
+func TestClient_ResolveSymbol_FallbackWhenCatalogueUnavailable(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{})
+	client := NewClient(server.URL, 2)
+
+	symbol, err := client.ResolveSymbol("BTC/EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "XXBTZEUR", symbol)
+}
+
+func TestClient_Fetch_RateLimited(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{
+		"/0/public/AssetPairs": assetPairsBody,
+		"/0/public/Ticker":     `{"error":[],"result":{"XXBTZUSD":{"c":["50000.0","1"],"v":["10","20"]}}}`,
+	})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+	client.SetRateLimit(0.001, 1, 10*time.Millisecond)
+
+	first := client.Fetch(context.Background(), "BTC/USD")
+	require.Empty(t, first.Error)
+	assert.Equal(t, "50000", first.Amount.String())
+
+	second := client.Fetch(context.Background(), "BTC/USD")
+	assert.True(t, second.IsRateLimited(), second.Error)
+	assert.Equal(t, Source, second.Source)
+}
+
+func TestClient_Fetch_ChecksStatusBeforeDecoding(t *testing.T) {
+	var tickerCalls atomic.Int32
+	status := http.StatusTooManyRequests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/0/public/AssetPairs" {
+			_, _ = w.Write([]byte(assetPairsBody))
+			return
+		}
+		tickerCalls.Add(1)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("<html>try again later</html>"))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	ltp := client.Fetch(context.Background(), "BTC/USD")
+	assert.True(t, ltp.IsRateLimited(), ltp.Error)
+	assert.Equal(t, int32(1), tickerCalls.Load(), "a 429 must not be retried")
+
+	status = http.StatusBadGateway
+	tickerCalls.Store(0)
+	ltp = client.Fetch(context.Background(), "BTC/USD")
+	assert.Equal(t, "kraken error: status 502", ltp.Error)
+	assert.Greater(t, tickerCalls.Load(), int32(1), "a 5xx is retried")
+}
+
+func TestClient_FetchMany(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/0/public/AssetPairs":
+			_, _ = w.Write([]byte(assetPairsBody))
+		case "/0/public/Ticker":
+			query = r.URL.Query().Get("pair")
+			_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":{"c":["50000.0","1"],"v":["10","20"]}}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	ltps := client.FetchMany(context.Background(), []domain.Pair{"BTC/USD", "ETH/EUR", "BTC/CHF"})
+	require.Len(t, ltps, 3)
+	assert.Equal(t, "XXBTZUSD,XETHZEUR", query)
+
+	assert.Equal(t, "50000", ltps[0].Amount.String())
+	assert.Contains(t, ltps[1].Error, "not found in response")
+	assert.True(t, ltps[2].IsUnsupported())
+}
+
+func TestClient_FetchTickers(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{
+		"/0/public/AssetPairs": assetPairsBody,
+		"/0/public/Ticker": `{"error":[],"result":{"XXBTZUSD":{
+			"a":["50010.0","1","1.000"],"b":["50000.0","2","2.000"],"c":["50005.0","0.1"],
+			"v":["100.5","250.25"],"p":["49900.0","49800.0"],"t":[10,20],
+			"l":["49000.0","48000.0"],"h":["51000.0","52000.0"],"o":"49500.0"}}}`,
+	})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	tickers := client.FetchTickers(context.Background(), []domain.Pair{"BTC/USD", "BTC/CHF"})
+	require.Len(t, tickers, 2)
+
+	ticker := tickers[0]
+	require.Empty(t, ticker.Error)
+	assert.Equal(t, "50005", ticker.Last.String())
+	assert.Equal(t, "50000", ticker.Bid.String())
+	assert.Equal(t, "50010", ticker.Ask.String())
+	assert.Equal(t, "10", ticker.Spread.String())
+	assert.Equal(t, "49500", ticker.Open.String())
+	assert.Equal(t, "52000", ticker.High.String())
+	assert.Equal(t, "48000", ticker.Low.String())
+	assert.Equal(t, "100.5", ticker.VolumeToday.String())
+	assert.Equal(t, "250.25", ticker.Volume24h.String())
+	assert.Equal(t, "49800", ticker.VWAP24h.String())
+
+	assert.Contains(t, tickers[1].Error, domain.UnsupportedPairPrefix)
+}
+
+func TestClient_FetchOrderBook(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{
+		"/0/public/AssetPairs": assetPairsBody,
+		"/0/public/Depth": `{"error":[],"result":{"XXBTZUSD":{
+			"asks":[["50020.0","1.0",1700000000],["50010.0","0.5",1700000000],["50010.0","0.25",1700000001]],
+			"bids":[["49990.0","2.0",1700000000],["50000.0","1.5",1700000000]]}}}`,
+	})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	book := client.FetchOrderBook(context.Background(), "BTC/USD", 10)
+	require.Empty(t, book.Error)
+	require.Len(t, book.Bids, 2)
+	require.Len(t, book.Asks, 2)
+
+	assert.Equal(t, "50000", book.Bids[0].Price.String())
+	assert.Equal(t, "50010", book.Asks[0].Price.String())
+	assert.Equal(t, "0.75", book.Asks[0].Volume.String())
+	assert.Equal(t, "50005", book.MidPrice.String())
+	assert.Equal(t, "10", book.Spread.String())
+
+	assert.Len(t, book.Truncate(1).Bids, 1)
+}
+
+func TestClient_FetchCandles(t *testing.T) {
+	server := newKrakenServer(t, map[string]string{
+		"/0/public/AssetPairs": assetPairsBody,
+		"/0/public/OHLC": `{"error":[],"result":{"XXBTZUSD":[
+			[1700000000,"50000.0","50100.0","49900.0","50050.0","50010.0","12.5",42],
+			[1700003600,"50050.0","50200.0","50000.0","50150.0","50100.0","8.25",30]
+		],"last":1700003600}}`,
+	})
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	series := client.FetchCandles(context.Background(), "BTC/USD", 60)
+	require.Empty(t, series.Error)
+	require.Len(t, series.Candles, 2)
+
+	first := series.Candles[0]
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), first.Time)
+	assert.Equal(t, "50000", first.Open.String())
+	assert.Equal(t, "50050", first.Close.String())
+	assert.Equal(t, "12.5", first.Volume.String())
+	assert.Equal(t, 42, first.Trades)
+
+	assert.Len(t, series.Since(time.Unix(1700003600, 0)).Candles, 1)
+}
+
+func TestClient_FetchTrades(t *testing.T) {
+	var since string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/0/public/AssetPairs":
+			_, _ = w.Write([]byte(assetPairsBody))
+		case "/0/public/Trades":
+			since = r.URL.Query().Get("since")
+			_, _ = w.Write([]byte(`{"error":[],"result":{"XXBTZUSD":[
+				["50000.0","0.1",1700000000.5,"b","m","",101],
+				["50000.5","0.3",1700000000.75,"b","m",""],
+				["50001.0","0.2",1700000001.25,"s","l","",102]
+			],"last":"1700000001250000000"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, 2)
+	require.NoError(t, client.LoadAssetPairs(context.Background()))
+
+	batch := client.FetchTrades(context.Background(), "BTC/USD", "1700000000000000000")
+	require.Empty(t, batch.Error)
+	assert.Equal(t, "1700000000000000000", since)
+	assert.Equal(t, "1700000001250000000", batch.Cursor)
+	require.Len(t, batch.Trades, 2, "rows without a trade id are skipped")
+
+	assert.Equal(t, int64(101), batch.Trades[0].ID)
+	assert.Equal(t, domain.SideBuy, batch.Trades[0].Side)
+	assert.Equal(t, domain.OrderMarket, batch.Trades[0].OrderType)
+	assert.Equal(t, domain.SideSell, batch.Trades[1].Side)
+	assert.Equal(t, domain.OrderLimit, batch.Trades[1].OrderType)
+	assert.Equal(t, "0.2", batch.Trades[1].Volume.String())
+}
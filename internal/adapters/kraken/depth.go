@@ -0,0 +1,112 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+type krakenDepthResp struct {
+	Error  []string                    `json:"error"`
+	Result map[string]krakenDepthEntry `json:"result"`
+}
+
+// Each level is [price, volume, timestamp] with price and volume as strings.
+type krakenDepthEntry struct {
+	Asks [][]interface{} `json:"asks"`
+	Bids [][]interface{} `json:"bids"`
+}
+
+// FetchOrderBook reads up to depth levels per side from the Depth endpoint.
+func (c *Client) FetchOrderBook(ctx context.Context, pair domain.Pair, depth int) domain.OrderBook {
+	symbolPair, err := c.ResolveSymbol(pair)
+	if err != nil {
+		return errorOrderBook(pair, unsupportedMessage(pair))
+	}
+
+	var parsed krakenDepthResp
+	path := fmt.Sprintf("Depth?pair=%s&count=%d", symbolPair, depth)
+	if err := c.getPublic(ctx, path, &parsed, func() []string { return parsed.Error }); err != nil {
+		return errorOrderBook(pair, c.failedLTP(ctx, pair, err).Error)
+	}
+
+	entry, ok := parsed.Result[symbolPair]
+	if !ok {
+		return errorOrderBook(pair, fmt.Sprintf("Pair %s not found in response", symbolPair))
+	}
+
+	bids, err := parseLevels(entry.Bids)
+	if err != nil {
+		return errorOrderBook(pair, fmt.Sprintf("Invalid bid level: %v", err))
+	}
+	asks, err := parseLevels(entry.Asks)
+	if err != nil {
+		return errorOrderBook(pair, fmt.Sprintf("Invalid ask level: %v", err))
+	}
+	if len(bids) == 0 || len(asks) == 0 {
+		return errorOrderBook(pair, "Order book is empty")
+	}
+
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price.GreaterThan(bids[j].Price) })
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price.LessThan(asks[j].Price) })
+
+	return domain.OrderBook{
+		Pair:      pair,
+		Bids:      bids,
+		Asks:      asks,
+		MidPrice:  bids[0].Price.Add(asks[0].Price).Div(decimal.NewFromInt(2)),
+		Spread:    asks[0].Price.Sub(bids[0].Price),
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
+
+// parseLevels merges levels quoted at the same price so each price appears once.
+func parseLevels(raw [][]interface{}) ([]domain.PriceLevel, error) {
+	index := make(map[string]int)
+	levels := make([]domain.PriceLevel, 0, len(raw))
+	for _, r := range raw {
+		if len(r) < 2 {
+			return nil, fmt.Errorf("expected price and volume, got %v", r)
+		}
+		price, err := decimalField(r[0])
+		if err != nil {
+			return nil, err
+		}
+		volume, err := decimalField(r[1])
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[price.String()]; ok {
+			levels[i].Volume = levels[i].Volume.Add(volume)
+			continue
+		}
+		index[price.String()] = len(levels)
+		levels = append(levels, domain.PriceLevel{Price: price, Volume: volume})
+	}
+	return levels, nil
+}
+
+func decimalField(v interface{}) (decimal.Decimal, error) {
+	switch x := v.(type) {
+	case string:
+		return decimal.NewFromString(x)
+	case float64:
+		return decimal.NewFromFloat(x), nil
+	default:
+		return decimal.Zero, fmt.Errorf("unexpected value %v", v)
+	}
+}
+
+func errorOrderBook(pair domain.Pair, msg string) domain.OrderBook {
+	return domain.OrderBook{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
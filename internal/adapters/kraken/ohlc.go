@@ -0,0 +1,109 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Intervals Kraken's OHLC endpoint accepts, in minutes.
+var ohlcIntervals = []int{1, 5, 15, 30, 60, 240, 1440, 10080, 21600}
+
+type krakenOHLCResp struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+func (c *Client) Intervals() []int {
+	return append([]int(nil), ohlcIntervals...)
+}
+
+// FetchCandles reads the most recent candles Kraken keeps for the pair at the given interval.
+func (c *Client) FetchCandles(ctx context.Context, pair domain.Pair, interval int) domain.CandleSeries {
+	symbolPair, err := c.ResolveSymbol(pair)
+	if err != nil {
+		return errorCandles(pair, interval, unsupportedMessage(pair))
+	}
+
+	var parsed krakenOHLCResp
+	path := fmt.Sprintf("OHLC?pair=%s&interval=%d", symbolPair, interval)
+	if err := c.getPublic(ctx, path, &parsed, func() []string { return parsed.Error }); err != nil {
+		return errorCandles(pair, interval, c.failedLTP(ctx, pair, err).Error)
+	}
+
+	raw, ok := parsed.Result[symbolPair]
+	if !ok {
+		return errorCandles(pair, interval, fmt.Sprintf("Pair %s not found in response", symbolPair))
+	}
+
+	// Each row is [time, open, high, low, close, vwap, volume, count].
+	var rows [][]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return errorCandles(pair, interval, fmt.Sprintf("Invalid OHLC format: %v", err))
+	}
+
+	candles := make([]domain.Candle, 0, len(rows))
+	for _, row := range rows {
+		candle, err := toCandle(row)
+		if err != nil {
+			return errorCandles(pair, interval, fmt.Sprintf("Invalid OHLC format: %v", err))
+		}
+		candles = append(candles, candle)
+	}
+
+	return domain.CandleSeries{
+		Pair:      pair,
+		Interval:  interval,
+		Candles:   candles,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
+
+func toCandle(row []interface{}) (domain.Candle, error) {
+	if len(row) < 8 {
+		return domain.Candle{}, fmt.Errorf("expected 8 fields, got %d", len(row))
+	}
+	ts, ok := row[0].(float64)
+	if !ok {
+		return domain.Candle{}, fmt.Errorf("unexpected time %v", row[0])
+	}
+	count, ok := row[7].(float64)
+	if !ok {
+		return domain.Candle{}, fmt.Errorf("unexpected trade count %v", row[7])
+	}
+
+	values := make([]decimal.Decimal, 6)
+	for i := range values {
+		d, err := decimalField(row[i+1])
+		if err != nil {
+			return domain.Candle{}, err
+		}
+		values[i] = d
+	}
+
+	return domain.Candle{
+		Time:   time.Unix(int64(ts), 0).UTC(),
+		Open:   values[0],
+		High:   values[1],
+		Low:    values[2],
+		Close:  values[3],
+		VWAP:   values[4],
+		Volume: values[5],
+		Trades: int(count),
+	}, nil
+}
+
+func errorCandles(pair domain.Pair, interval int, msg string) domain.CandleSeries {
+	return domain.CandleSeries{
+		Pair:      pair,
+		Interval:  interval,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
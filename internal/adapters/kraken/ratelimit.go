@@ -0,0 +1,55 @@
+package kraken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrRateLimited = fmt.Errorf("%s: kraken request budget exhausted", domain.RateLimitedPrefix)
+	// ErrUpstreamRateLimited is Kraken itself answering 429, as opposed to our own budget running out.
+	ErrUpstreamRateLimited = fmt.Errorf("%s: kraken returned status 429", domain.RateLimitedPrefix)
+)
+
+// SetRateLimit caps outgoing requests with a token bucket; callers queue for at most maxWait before getting ErrRateLimited.
+func (c *Client) SetRateLimit(requestsPerSecond float64, burst int, maxWait time.Duration) {
+	if requestsPerSecond <= 0 {
+		c.limiter = nil
+		return
+	}
+	if burst <= 0 {
+		burst = 1
+	}
+	c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	c.maxWait = maxWait
+}
+
+func (c *Client) wait(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	waitCtx := ctx
+	if c.maxWait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, c.maxWait)
+		defer cancel()
+	}
+
+	if err := c.limiter.Wait(waitCtx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return ErrRateLimited
+	}
+	return nil
+}
+
+func isRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrUpstreamRateLimited)
+}
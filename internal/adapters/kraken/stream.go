@@ -0,0 +1,342 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	streamWriteTimeout  = 5 * time.Second
+	defaultStreamMaxAge = time.Minute
+)
+
+// Fetcher is used by the stream client to answer pairs it has no recent streamed price for.
+type Fetcher interface {
+	Fetch(ctx context.Context, pair domain.Pair) domain.LTP
+}
+
+// Sink receives every streamed price; LTPService.Ingest caches, records and publishes it.
+type Sink interface {
+	Ingest(ctx context.Context, ltp domain.LTP)
+}
+
+// StreamClient is a MarketDataProvider fed by Kraken's WebSocket v2 ticker and trade channels.
+// Streamed prices are only served while the socket is up and they are younger than maxAge.
+type StreamClient struct {
+	url      string
+	pairs    []domain.Pair
+	symbols  map[string]domain.Pair
+	sink     Sink
+	fallback Fetcher
+	maxAge   time.Duration
+
+	mu     sync.RWMutex
+	latest map[domain.Pair]domain.LTP
+	conn   *websocket.Conn
+
+	dialer     *websocket.Dialer
+	newBackOff func() backoff.BackOff
+	ctx        context.Context
+	cancel     context.CancelFunc
+	done       chan struct{}
+	startOnce  sync.Once
+	stopOnce   sync.Once
+}
+
+type wsRequest struct {
+	Method string   `json:"method"`
+	Params wsParams `json:"params"`
+}
+
+type wsParams struct {
+	Channel string   `json:"channel"`
+	Symbol  []string `json:"symbol"`
+}
+
+type wsMessage struct {
+	Channel string          `json:"channel"`
+	Type    string          `json:"type"`
+	Method  string          `json:"method"`
+	Success *bool           `json:"success"`
+	Error   string          `json:"error"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type wsTicker struct {
+	Symbol string           `json:"symbol"`
+	Last   decimal.Decimal  `json:"last"`
+	Volume *decimal.Decimal `json:"volume"`
+}
+
+type wsTrade struct {
+	Symbol string          `json:"symbol"`
+	Price  decimal.Decimal `json:"price"`
+}
+
+func NewStreamClient(url string, pairs []domain.Pair, fallback Fetcher) *StreamClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	symbols := make(map[string]domain.Pair, len(pairs))
+	for _, p := range pairs {
+		symbols[toWSSymbol(p)] = p
+	}
+	return &StreamClient{
+		url:      url,
+		pairs:    pairs,
+		symbols:  symbols,
+		fallback: fallback,
+		maxAge:   defaultStreamMaxAge,
+		latest:   make(map[domain.Pair]domain.LTP),
+		dialer:   websocket.DefaultDialer,
+		newBackOff: func() backoff.BackOff {
+			b := backoff.NewExponentialBackOff()
+			b.MaxElapsedTime = 0
+			return b
+		},
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+}
+
+// SetSink routes streamed prices to sink. Must be called before Start.
+func (s *StreamClient) SetSink(sink Sink) {
+	s.sink = sink
+}
+
+// SetMaxAge bounds how old a streamed price may be before Fetch asks the fallback instead.
+func (s *StreamClient) SetMaxAge(d time.Duration) {
+	if d > 0 {
+		s.maxAge = d
+	}
+}
+
+func (s *StreamClient) Start() {
+	s.startOnce.Do(func() {
+		go s.run()
+	})
+}
+
+func (s *StreamClient) Stop() {
+	s.stopOnce.Do(func() {
+		s.cancel()
+		s.mu.Lock()
+		if s.conn != nil {
+			if err := s.conn.Close(); err != nil {
+				log.GetInstance().Debug("Error closing Kraken stream: %v", err)
+			}
+		}
+		s.mu.Unlock()
+	})
+	s.startOnce.Do(func() { close(s.done) })
+	<-s.done
+}
+
+func (s *StreamClient) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	s.mu.RLock()
+	ltp, ok := s.latest[pair]
+	connected := s.conn != nil
+	s.mu.RUnlock()
+	if ok && connected && time.Since(ltp.Timestamp) < s.maxAge {
+		return ltp
+	}
+
+	if s.fallback != nil {
+		return s.fallback.Fetch(ctx, pair)
+	}
+
+	return domain.LTP{
+		Pair:      pair,
+		Error:     fmt.Sprintf("no recent streamed price available for pair %s", pair),
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
+
+// Markets passes on the fallback's market list, since it answers every pair the stream does not carry.
+func (s *StreamClient) Markets() []domain.Pair {
+	if m, ok := s.fallback.(interface{ Markets() []domain.Pair }); ok {
+		return m.Markets()
+	}
+	return nil
+}
+
+func (s *StreamClient) run() {
+	defer close(s.done)
+
+	b := s.newBackOff()
+	for {
+		connected, err := s.stream()
+		if s.ctx.Err() != nil {
+			return
+		}
+		if connected {
+			b.Reset()
+		}
+
+		wait := b.NextBackOff()
+		log.GetInstance().Warn("Kraken stream disconnected: %v. Reconnecting in %s", err, wait)
+		select {
+		case <-time.After(wait):
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// stream runs one connection until it fails; connected reports whether the subscription went through.
+func (s *StreamClient) stream() (connected bool, err error) {
+	conn, _, err := s.dialer.DialContext(s.ctx, s.url, nil)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	if s.ctx.Err() != nil {
+		s.mu.Unlock()
+		return false, conn.Close()
+	}
+	s.conn = conn
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+		if err := conn.Close(); err != nil {
+			log.GetInstance().Debug("Error closing Kraken stream: %v", err)
+		}
+	}()
+
+	if err := s.subscribe(conn); err != nil {
+		return false, err
+	}
+	log.GetInstance().Info("Subscribed to Kraken stream for %d pairs", len(s.pairs))
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		s.handleMessage(payload)
+	}
+}
+
+func (s *StreamClient) subscribe(conn *websocket.Conn) error {
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+
+	for _, channel := range []string{"ticker", "trade"} {
+		req := wsRequest{
+			Method: "subscribe",
+			Params: wsParams{Channel: channel, Symbol: symbols},
+		}
+		if err := conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+			return err
+		}
+		if err := conn.WriteJSON(req); err != nil {
+			return fmt.Errorf("subscribe to %s: %w", channel, err)
+		}
+	}
+	return nil
+}
+
+func (s *StreamClient) handleMessage(payload []byte) {
+	var msg wsMessage
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		log.GetInstance().Debug("Ignoring malformed Kraken stream message: %v", err)
+		return
+	}
+
+	if msg.Success != nil && !*msg.Success {
+		log.GetInstance().Warn("Kraken stream %s failed: %s", msg.Method, msg.Error)
+		return
+	}
+
+	// Ticker messages carry no exchange time, so both channels are stamped on receipt to keep them comparable.
+	received := time.Now().UTC()
+	switch msg.Channel {
+	case "ticker":
+		var tickers []wsTicker
+		if err := json.Unmarshal(msg.Data, &tickers); err != nil {
+			log.GetInstance().Debug("Ignoring malformed Kraken ticker: %v", err)
+			return
+		}
+		for _, t := range tickers {
+			s.update(t.Symbol, t.Last, t.Volume, received)
+		}
+	case "trade":
+		var trades []wsTrade
+		if err := json.Unmarshal(msg.Data, &trades); err != nil {
+			log.GetInstance().Debug("Ignoring malformed Kraken trade: %v", err)
+			return
+		}
+		for _, t := range trades {
+			s.update(t.Symbol, t.Price, nil, received)
+		}
+	}
+}
+
+// update records a price; trades carry no volume, so the last ticker volume is kept for them.
+func (s *StreamClient) update(symbol string, price decimal.Decimal, volume *decimal.Decimal, ts time.Time) {
+	pair, ok := s.symbols[symbol]
+	if !ok || price.IsZero() {
+		return
+	}
+	if ts.IsZero() {
+		ts = time.Now().UTC()
+	}
+
+	ltp := domain.LTP{
+		Pair:      pair,
+		Amount:    price,
+		Timestamp: ts,
+		Source:    Source,
+		Volume:    volume,
+	}
+
+	s.mu.Lock()
+	prev, exists := s.latest[pair]
+	if exists && prev.Timestamp.After(ts) {
+		s.mu.Unlock()
+		return
+	}
+	if ltp.Volume == nil && exists {
+		ltp.Volume = prev.Volume
+	}
+	s.latest[pair] = ltp
+	s.mu.Unlock()
+
+	if s.sink != nil {
+		s.sink.Ingest(s.ctx, ltp)
+	}
+}
+
+// WebSocket v2 uses BTC/DOGE where the REST API still uses XBT/XDG.
+func toWSSymbol(pair domain.Pair) string {
+	symbol := strings.ToUpper(strings.TrimSpace(string(pair)))
+	base, quote, found := strings.Cut(symbol, "/")
+	if !found {
+		return symbol
+	}
+	for ws, rest := range assetAliases {
+		if base == rest {
+			base = ws
+		}
+		if quote == rest {
+			quote = ws
+		}
+	}
+	return base + "/" + quote
+}
@@ -0,0 +1,210 @@
+package kraken
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKrakenWS struct {
+	server        *httptest.Server
+	connections   atomic.Int32
+	subscriptions chan wsRequest
+	onConnect     func(conn *websocket.Conn, n int32)
+}
+
+func newFakeKrakenWS(t *testing.T, onConnect func(conn *websocket.Conn, n int32)) *fakeKrakenWS {
+	t.Helper()
+	fake := &fakeKrakenWS{
+		subscriptions: make(chan wsRequest, 16),
+		onConnect:     onConnect,
+	}
+	upgrader := websocket.Upgrader{}
+	fake.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		n := fake.connections.Add(1)
+
+		for i := 0; i < 2; i++ {
+			var req wsRequest
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			fake.subscriptions <- req
+		}
+		fake.onConnect(conn, n)
+	}))
+	t.Cleanup(fake.server.Close)
+	return fake
+}
+
+func (f *fakeKrakenWS) url() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+// recordingSink collects what the stream hands to the service.
+type recordingSink struct {
+	mu   sync.Mutex
+	ltps []domain.LTP
+}
+
+func (r *recordingSink) Ingest(_ context.Context, ltp domain.LTP) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ltps = append(r.ltps, ltp)
+}
+
+func (r *recordingSink) received() []domain.LTP {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.LTP(nil), r.ltps...)
+}
+
+func newTestStreamClient(url string, pairs []domain.Pair, fallback Fetcher) *StreamClient {
+	s := NewStreamClient(url, pairs, fallback)
+	s.newBackOff = func() backoff.BackOff {
+		return backoff.NewConstantBackOff(10 * time.Millisecond)
+	}
+	return s
+}
+
+func TestStreamClient_TradeUpdatesSinkAndFetch(t *testing.T) {
+	// The exchange's trade time lags our clock; the update is stamped on receipt instead.
+	tradeTime := time.Now().UTC().Add(-time.Second)
+	start := time.Now().UTC()
+	fake := newFakeKrakenWS(t, func(conn *websocket.Conn, _ int32) {
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"channel":"heartbeat"}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(
+			`{"channel":"trade","type":"update","data":[{"symbol":"BTC/USD","side":"buy","price":65000.12345678,"qty":0.1,"timestamp":"`+
+				tradeTime.Format(time.RFC3339Nano)+`"}]}`))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	sink := &recordingSink{}
+	stream := newTestStreamClient(fake.url(), []domain.Pair{"BTC/USD"}, nil)
+	stream.SetSink(sink)
+	stream.Start()
+	defer stream.Stop()
+
+	sub := <-fake.subscriptions
+	assert.Equal(t, "subscribe", sub.Method)
+	assert.Equal(t, []string{"BTC/USD"}, sub.Params.Symbol)
+
+	require.Eventually(t, func() bool {
+		return len(sink.received()) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	ltp := stream.Fetch(context.Background(), "BTC/USD")
+	assert.Empty(t, ltp.Error)
+	assert.Equal(t, "65000.12345678", ltp.Amount.String())
+	assert.False(t, ltp.Timestamp.Before(start), "got %v", ltp.Timestamp)
+}
+
+func TestStreamClient_TradeAfterTickerIsNotDropped(t *testing.T) {
+	stream := NewStreamClient("", []domain.Pair{"BTC/USD"}, nil)
+	lagged := time.Now().UTC().Add(-2 * time.Second).Format(time.RFC3339Nano)
+
+	stream.handleMessage([]byte(`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/USD","last":65000,"volume":12.5}]}`))
+	stream.handleMessage([]byte(`{"channel":"trade","type":"update","data":[{"symbol":"BTC/USD","price":65010,"qty":0.1,"timestamp":"` + lagged + `"}]}`))
+
+	stream.mu.Lock()
+	ltp := stream.latest["BTC/USD"]
+	stream.mu.Unlock()
+	assert.Equal(t, "65010", ltp.Amount.String(), "a trade received after a ticker must replace it")
+	require.NotNil(t, ltp.Volume)
+	assert.Equal(t, "12.5", ltp.Volume.String())
+
+	stream.handleMessage([]byte(`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/USD","last":65020}]}`))
+	stream.mu.Lock()
+	ltp = stream.latest["BTC/USD"]
+	stream.mu.Unlock()
+	assert.Equal(t, "65020", ltp.Amount.String())
+}
+
+func TestStreamClient_FallsBackWhenStaleOrDisconnected(t *testing.T) {
+	release := make(chan struct{})
+	fake := newFakeKrakenWS(t, func(conn *websocket.Conn, n int32) {
+		if n > 1 {
+			_, _, _ = conn.ReadMessage()
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(
+			`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/USD","last":65000}]}`))
+		<-release
+	})
+
+	fallback := mocks.NewMockMarketDataProvider()
+	fallback.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Amount: decimal.NewFromInt(64000), Timestamp: time.Now()})
+	stream := newTestStreamClient(fake.url(), []domain.Pair{"BTC/USD"}, fallback)
+	stream.SetMaxAge(200 * time.Millisecond)
+	// Keep the socket down long enough to observe it.
+	stream.newBackOff = func() backoff.BackOff { return backoff.NewConstantBackOff(time.Second) }
+	stream.Start()
+	defer stream.Stop()
+
+	require.Eventually(t, func() bool {
+		return stream.Fetch(context.Background(), "BTC/USD").Amount.String() == "65000"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Past maxAge the streamed price is no longer served.
+	require.Eventually(t, func() bool {
+		return stream.Fetch(context.Background(), "BTC/USD").Amount.String() == "64000"
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// While the socket is down, even a recent price goes to REST.
+	stream.SetMaxAge(time.Hour)
+	close(release)
+	require.Eventually(t, func() bool {
+		stream.mu.RLock()
+		defer stream.mu.RUnlock()
+		return stream.conn == nil
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, "64000", stream.Fetch(context.Background(), "BTC/USD").Amount.String())
+}
+
+func TestStreamClient_ReconnectsAndResubscribes(t *testing.T) {
+	fake := newFakeKrakenWS(t, func(conn *websocket.Conn, n int32) {
+		if n == 1 {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(
+			`{"channel":"ticker","type":"update","data":[{"symbol":"BTC/EUR","last":59000.5}]}`))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	stream := newTestStreamClient(fake.url(), []domain.Pair{"XBT/EUR"}, nil)
+	stream.Start()
+	defer stream.Stop()
+
+	require.Eventually(t, func() bool {
+		return stream.Fetch(context.Background(), "XBT/EUR").Error == ""
+	}, 2*time.Second, 10*time.Millisecond)
+
+	assert.GreaterOrEqual(t, fake.connections.Load(), int32(2))
+	assert.Equal(t, "59000.5", stream.Fetch(context.Background(), "XBT/EUR").Amount.String())
+}
+
+func TestStreamClient_FetchWithoutData(t *testing.T) {
+	stream := NewStreamClient("ws://127.0.0.1:0", []domain.Pair{"BTC/USD"}, nil)
+	defer stream.Stop()
+
+	ltp := stream.Fetch(context.Background(), "BTC/USD")
+	assert.NotEmpty(t, ltp.Error)
+	assert.Equal(t, domain.Pair("BTC/USD"), ltp.Pair)
+}
@@ -0,0 +1,89 @@
+package kraken
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// FetchTickers returns full ticker snapshots for pairs from a single Ticker request, in the order given.
+func (c *Client) FetchTickers(ctx context.Context, pairs []domain.Pair) []domain.Ticker {
+	out := make([]domain.Ticker, len(pairs))
+	symbols, query := c.resolveAll(ctx, pairs)
+	for i, pair := range pairs {
+		if symbols[i] == "" {
+			out[i] = errorTicker(pair, unsupportedMessage(pair))
+		}
+	}
+	if len(query) == 0 {
+		return out
+	}
+
+	result, err := c.fetchTicker(ctx, query)
+	for i, pair := range pairs {
+		if symbols[i] == "" {
+			continue
+		}
+		if err != nil {
+			out[i] = errorTicker(pair, c.failedLTP(ctx, pair, err).Error)
+			continue
+		}
+		entry, ok := result[symbols[i]]
+		if !ok {
+			out[i] = errorTicker(pair, fmt.Sprintf("Pair %s not found in response", symbols[i]))
+			continue
+		}
+		out[i] = toTicker(pair, entry)
+	}
+	return out
+}
+
+func toTicker(pair domain.Pair, entry krakenTickerEntry) domain.Ticker {
+	var parseErr error
+	field := func(values []string, i int) decimal.Decimal {
+		if i >= len(values) || values[i] == "" {
+			return decimal.Zero
+		}
+		d, err := decimal.NewFromString(values[i])
+		if err != nil && parseErr == nil {
+			parseErr = err
+		}
+		return d
+	}
+
+	t := domain.Ticker{
+		Pair:        pair,
+		Last:        field(entry.C, 0),
+		Bid:         field(entry.B, 0),
+		Ask:         field(entry.A, 0),
+		Open:        field([]string{entry.O}, 0),
+		High:        field(entry.H, 1),
+		Low:         field(entry.L, 1),
+		VolumeToday: field(entry.V, 0),
+		Volume24h:   field(entry.V, 1),
+		VWAPToday:   field(entry.P, 0),
+		VWAP24h:     field(entry.P, 1),
+		Timestamp:   time.Now().UTC(),
+		Source:      Source,
+	}
+	if parseErr != nil {
+		return errorTicker(pair, fmt.Sprintf("Invalid ticker format: %v", parseErr))
+	}
+	if !t.Last.IsPositive() {
+		return errorTicker(pair, "No last trade price data available")
+	}
+	t.Spread = t.Ask.Sub(t.Bid)
+	return t
+}
+
+func errorTicker(pair domain.Pair, msg string) domain.Ticker {
+	return domain.Ticker{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
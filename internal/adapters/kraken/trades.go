@@ -0,0 +1,124 @@
+package kraken
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+type krakenTradesResp struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// FetchTrades returns trades after the since cursor (empty for the most recent ones Kraken keeps).
+func (c *Client) FetchTrades(ctx context.Context, pair domain.Pair, since string) domain.TradeBatch {
+	symbolPair, err := c.ResolveSymbol(pair)
+	if err != nil {
+		return errorTrades(pair, unsupportedMessage(pair))
+	}
+
+	query := url.Values{"pair": {symbolPair}}
+	if since != "" {
+		query.Set("since", since)
+	}
+
+	var parsed krakenTradesResp
+	if err := c.getPublic(ctx, "Trades?"+query.Encode(), &parsed, func() []string { return parsed.Error }); err != nil {
+		return errorTrades(pair, c.failedLTP(ctx, pair, err).Error)
+	}
+
+	raw, ok := parsed.Result[symbolPair]
+	if !ok {
+		return errorTrades(pair, fmt.Sprintf("Pair %s not found in response", symbolPair))
+	}
+
+	// Each row is [price, volume, time, side, order type, misc, trade id].
+	var rows [][]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return errorTrades(pair, fmt.Sprintf("Invalid trades format: %v", err))
+	}
+
+	trades := make([]domain.Trade, 0, len(rows))
+	skipped := 0
+	for _, row := range rows {
+		trade, err := toTrade(row)
+		if err != nil {
+			return errorTrades(pair, fmt.Sprintf("Invalid trades format: %v", err))
+		}
+		// Feeds dedupe and page by trade id, so a row without one has no place in them.
+		if trade.ID == 0 {
+			skipped++
+			continue
+		}
+		trades = append(trades, trade)
+	}
+	if skipped > 0 {
+		log.GetInstance().Debug("Skipped %d Kraken trades without an id for %s", skipped, pair)
+	}
+
+	var cursor string
+	if last, ok := parsed.Result["last"]; ok {
+		_ = json.Unmarshal(last, &cursor)
+	}
+
+	return domain.TradeBatch{
+		Pair:      pair,
+		Trades:    trades,
+		Cursor:    cursor,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
+
+func toTrade(row []interface{}) (domain.Trade, error) {
+	if len(row) < 5 {
+		return domain.Trade{}, fmt.Errorf("expected at least 5 fields, got %d", len(row))
+	}
+	price, err := decimalField(row[0])
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	volume, err := decimalField(row[1])
+	if err != nil {
+		return domain.Trade{}, err
+	}
+	ts, ok := row[2].(float64)
+	if !ok {
+		return domain.Trade{}, fmt.Errorf("unexpected time %v", row[2])
+	}
+
+	trade := domain.Trade{
+		Price:     price,
+		Volume:    volume,
+		Side:      domain.SideBuy,
+		OrderType: domain.OrderMarket,
+		Time:      time.Unix(0, int64(ts*float64(time.Second))).UTC(),
+	}
+	if row[3] == "s" {
+		trade.Side = domain.SideSell
+	}
+	if row[4] == "l" {
+		trade.OrderType = domain.OrderLimit
+	}
+	if len(row) > 6 {
+		if id, ok := row[6].(float64); ok {
+			trade.ID = int64(id)
+		}
+	}
+	return trade, nil
+}
+
+func errorTrades(pair domain.Pair, msg string) domain.TradeBatch {
+	return domain.TradeBatch{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    Source,
+	}
+}
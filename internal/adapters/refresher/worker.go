@@ -1,6 +1,7 @@
 package refresher
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -8,11 +9,18 @@ import (
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
 )
 
+// Leader reports whether this replica should refresh; see cluster.Elector.
+type Leader interface {
+	IsLeader() bool
+}
+
 type Refresher struct {
 	service  *application.LTPService
 	pairs    []domain.Pair
 	interval time.Duration
-	quit     chan struct{}
+	leader   Leader
+	ctx      context.Context
+	cancel   context.CancelFunc
 	once     sync.Once
 }
 
@@ -21,14 +29,21 @@ func NewRefresher(s *application.LTPService, pairs []domain.Pair, interval time.
 	for _, p := range pairs {
 		dpairs = append(dpairs, domain.Pair(p))
 	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Refresher{
 		service:  s,
 		pairs:    dpairs,
 		interval: interval,
-		quit:     make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 }
 
+// SetLeader makes the refresher skip ticks while l does not hold leadership. Must be called before Start.
+func (r *Refresher) SetLeader(l Leader) {
+	r.leader = l
+}
+
 func (r *Refresher) Start() {
 	go func() {
 		t := time.NewTicker(r.interval)
@@ -36,8 +51,11 @@ func (r *Refresher) Start() {
 		for {
 			select {
 			case <-t.C:
-				r.service.RefreshPairs(r.pairs)
-			case <-r.quit:
+				if r.leader != nil && !r.leader.IsLeader() {
+					continue
+				}
+				r.service.RefreshPairs(r.ctx, r.pairs)
+			case <-r.ctx.Done():
 				return
 			}
 		}
@@ -46,7 +64,7 @@ func (r *Refresher) Start() {
 
 func (r *Refresher) Stop() {
 	r.once.Do(func() {
-		close(r.quit)
+		r.cancel()
 	})
 
 }
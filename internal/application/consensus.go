@@ -0,0 +1,248 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ConsensusSource = "consensus"
+
+	ConsensusMedian = "median"
+	ConsensusVWAP   = "vwap"
+)
+
+type ConsensusOptions struct {
+	Method       string
+	MaxDeviation decimal.Decimal
+	MaxAge       time.Duration
+	MinSources   int
+}
+
+// ConsensusProvider asks several providers for the same pair and combines the quotes that agree.
+type ConsensusProvider struct {
+	names     []string
+	providers []MarketDataProvider
+	opts      ConsensusOptions
+}
+
+func NewConsensusProvider(registry *ProviderRegistry, sources []string, opts ConsensusOptions) (*ConsensusProvider, error) {
+	c := &ConsensusProvider{opts: opts}
+	if c.opts.Method == "" {
+		c.opts.Method = ConsensusMedian
+	}
+	if c.opts.Method != ConsensusMedian && c.opts.Method != ConsensusVWAP {
+		return nil, fmt.Errorf("unknown consensus method: %s", opts.Method)
+	}
+	if c.opts.MinSources <= 0 {
+		c.opts.MinSources = 1
+	}
+
+	for _, name := range sources {
+		p, ok := registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSource, name)
+		}
+		c.names = append(c.names, name)
+		c.providers = append(c.providers, p)
+	}
+	if len(c.providers) == 0 {
+		return nil, fmt.Errorf("consensus requires at least one source")
+	}
+	return c, nil
+}
+
+func (c *ConsensusProvider) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	quotes := c.collect(ctx, pair)
+	if ctx.Err() != nil {
+		return domain.LTP{
+			Pair:      pair,
+			Error:     fmt.Sprintf("%v: %v", domain.ErrCanceled, ctx.Err()),
+			Timestamp: time.Now().UTC(),
+			Source:    ConsensusSource,
+		}
+	}
+
+	c.discardOutliers(quotes)
+
+	var used []domain.Quote
+	for _, q := range quotes {
+		if q.Used {
+			used = append(used, q)
+		}
+	}
+
+	consensus := &domain.Consensus{Method: c.opts.Method, Quotes: quotes}
+	if len(used) < c.opts.MinSources {
+		return domain.LTP{
+			Pair:      pair,
+			Error:     fmt.Sprintf("insufficient quotes for consensus: got %d, need %d", len(used), c.opts.MinSources),
+			Timestamp: time.Now().UTC(),
+			Source:    ConsensusSource,
+			Consensus: consensus,
+		}
+	}
+
+	amount, method := median(used), ConsensusMedian
+	if c.opts.Method == ConsensusVWAP {
+		if weighted, ok := c.volumeWeighted(quotes); ok {
+			amount, method = weighted, ConsensusVWAP
+		} else {
+			log.GetInstance().Debug("Too few volumes reported for %s, falling back to median", pair)
+		}
+	}
+	consensus.Method = method
+
+	// The result is as recent as the latest price that went into it.
+	newest := time.Time{}
+	for _, q := range quotes {
+		if q.Used && q.Timestamp.After(newest) {
+			newest = q.Timestamp
+		}
+	}
+
+	return domain.LTP{
+		Pair:      pair,
+		Amount:    amount,
+		Timestamp: newest,
+		Source:    ConsensusSource,
+		Consensus: consensus,
+	}
+}
+
+// Markets lists what any source trades; a leg that then gets too few quotes fails like any other fetch.
+func (c *ConsensusProvider) Markets() []domain.Pair {
+	return unionMarkets(c.providers)
+}
+
+func (c *ConsensusProvider) collect(ctx context.Context, pair domain.Pair) []domain.Quote {
+	quotes := make([]domain.Quote, len(c.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range c.providers {
+		wg.Add(1)
+		go func(i int, p MarketDataProvider) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					log.GetInstance().Debug("PANIC in %s Fetch for pair %s: %v", c.names[i], pair, r)
+					quotes[i] = domain.Quote{Source: c.names[i], Reason: "provider panicked"}
+				}
+			}()
+			quotes[i] = c.toQuote(c.names[i], p.Fetch(ctx, pair))
+		}(i, p)
+	}
+	wg.Wait()
+
+	return quotes
+}
+
+func (c *ConsensusProvider) toQuote(source string, ltp domain.LTP) domain.Quote {
+	q := domain.Quote{
+		Source:    source,
+		Amount:    ltp.Amount,
+		Volume:    ltp.Volume,
+		Timestamp: ltp.Timestamp,
+	}
+	switch {
+	case ltp.Error != "":
+		q.Reason = ltp.Error
+	case !ltp.Amount.IsPositive():
+		q.Reason = "no price"
+	case c.opts.MaxAge > 0 && time.Since(ltp.Timestamp) > c.opts.MaxAge:
+		q.Reason = "stale"
+	default:
+		q.Used = true
+	}
+	return q
+}
+
+func (c *ConsensusProvider) discardOutliers(quotes []domain.Quote) {
+	if !c.opts.MaxDeviation.IsPositive() {
+		return
+	}
+
+	var used []domain.Quote
+	for _, q := range quotes {
+		if q.Used {
+			used = append(used, q)
+		}
+	}
+	if len(used) == 2 {
+		// Two quotes have no majority to pick an outlier from; if they disagree, neither can be trusted.
+		mid := median(used)
+		deviation := used[0].Amount.Sub(mid).Abs().Div(mid)
+		if deviation.GreaterThan(c.opts.MaxDeviation) {
+			for i := range quotes {
+				if quotes[i].Used {
+					quotes[i].Used = false
+					quotes[i].Reason = fmt.Sprintf("sources disagree by %s%%", deviation.Mul(decimal.NewFromInt(200)).StringFixed(2))
+				}
+			}
+		}
+		return
+	}
+	if len(used) < 2 {
+		return
+	}
+
+	mid := median(used)
+	for i := range quotes {
+		if !quotes[i].Used {
+			continue
+		}
+		deviation := quotes[i].Amount.Sub(mid).Abs().Div(mid)
+		if deviation.GreaterThan(c.opts.MaxDeviation) {
+			quotes[i].Used = false
+			quotes[i].Reason = fmt.Sprintf("deviates %s%% from median", deviation.Mul(decimal.NewFromInt(100)).StringFixed(2))
+		}
+	}
+}
+
+func median(quotes []domain.Quote) decimal.Decimal {
+	amounts := make([]decimal.Decimal, len(quotes))
+	for i, q := range quotes {
+		amounts[i] = q.Amount
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].LessThan(amounts[j]) })
+
+	n := len(amounts)
+	if n%2 == 1 {
+		return amounts[n/2]
+	}
+	return amounts[n/2-1].Add(amounts[n/2]).Div(decimal.NewFromInt(2))
+}
+
+// volumeWeighted averages the quotes that report volume, provided enough of them do, and marks the
+// rest unused so the breakdown matches the price.
+func (c *ConsensusProvider) volumeWeighted(quotes []domain.Quote) (decimal.Decimal, bool) {
+	total := decimal.Zero
+	weighted := decimal.Zero
+	weighed := 0
+	for _, q := range quotes {
+		if !q.Used || q.Volume == nil || !q.Volume.IsPositive() {
+			continue
+		}
+		total = total.Add(*q.Volume)
+		weighted = weighted.Add(q.Amount.Mul(*q.Volume))
+		weighed++
+	}
+	if weighed == 0 || weighed < c.opts.MinSources {
+		return decimal.Zero, false
+	}
+
+	for i := range quotes {
+		if quotes[i].Used && (quotes[i].Volume == nil || !quotes[i].Volume.IsPositive()) {
+			quotes[i].Used = false
+			quotes[i].Reason = "no volume reported"
+		}
+	}
+	return weighted.Div(total), true
+}
@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConsensusRegistry(quotes map[string]domain.LTP) *ProviderRegistry {
+	registry := NewProviderRegistry()
+	for name, ltp := range quotes {
+		p := mocks.NewMockMarketDataProvider()
+		p.SetResponse(ltp.Pair, ltp)
+		registry.Register(name, p)
+	}
+	return registry
+}
+
+func withVolume(ltp domain.LTP, volume string) domain.LTP {
+	v := decimal.RequireFromString(volume)
+	ltp.Volume = &v
+	return ltp
+}
+
+func TestConsensusProvider_MedianDiscardsOutlier(t *testing.T) {
+	now := time.Now()
+	registry := newConsensusRegistry(map[string]domain.LTP{
+		"kraken":   createLTP("BTC/USD", "50000", now),
+		"coinbase": createLTP("BTC/USD", "50100", now),
+		"bitstamp": createLTP("BTC/USD", "50200", now),
+		"binance":  createLTP("BTC/USD", "60000", now),
+	})
+
+	consensus, err := NewConsensusProvider(registry, []string{"kraken", "coinbase", "bitstamp", "binance"}, ConsensusOptions{
+		Method:       ConsensusMedian,
+		MaxDeviation: decimal.RequireFromString("0.02"),
+		MinSources:   2,
+	})
+	require.NoError(t, err)
+
+	ltp := consensus.Fetch(context.Background(), "BTC/USD")
+	require.Empty(t, ltp.Error)
+	assert.Equal(t, "50100", ltp.Amount.String())
+	assert.Equal(t, ConsensusSource, ltp.Source)
+	require.NotNil(t, ltp.Consensus)
+	require.Len(t, ltp.Consensus.Quotes, 4)
+
+	for _, q := range ltp.Consensus.Quotes {
+		if q.Source == "binance" {
+			assert.False(t, q.Used)
+			assert.Contains(t, q.Reason, "deviates")
+		} else {
+			assert.True(t, q.Used, q.Source)
+		}
+	}
+}
+
+func TestConsensusProvider_VolumeWeighted(t *testing.T) {
+	now := time.Now()
+	registry := newConsensusRegistry(map[string]domain.LTP{
+		"kraken":   withVolume(createLTP("BTC/USD", "50000", now), "3"),
+		"coinbase": withVolume(createLTP("BTC/USD", "50400", now), "1"),
+	})
+
+	consensus, err := NewConsensusProvider(registry, []string{"kraken", "coinbase"}, ConsensusOptions{Method: ConsensusVWAP})
+	require.NoError(t, err)
+
+	ltp := consensus.Fetch(context.Background(), "BTC/USD")
+	require.Empty(t, ltp.Error)
+	assert.Equal(t, "50100", ltp.Amount.String())
+	assert.Equal(t, ConsensusVWAP, ltp.Consensus.Method)
+}
+
+func TestConsensusProvider_StaleAndFailedQuotes(t *testing.T) {
+	now := time.Now()
+	registry := newConsensusRegistry(map[string]domain.LTP{
+		"kraken":   createLTP("BTC/USD", "50000", now.Add(-time.Hour)),
+		"coinbase": {Pair: "BTC/USD", Error: "timeout", Timestamp: now},
+		"bitstamp": createLTP("BTC/USD", "50200", now),
+	})
+
+	consensus, err := NewConsensusProvider(registry, []string{"kraken", "coinbase", "bitstamp"}, ConsensusOptions{
+		MaxAge:     time.Minute,
+		MinSources: 2,
+	})
+	require.NoError(t, err)
+
+	ltp := consensus.Fetch(context.Background(), "BTC/USD")
+	assert.Contains(t, ltp.Error, "insufficient quotes")
+	require.NotNil(t, ltp.Consensus)
+}
+
+func TestNewConsensusProvider_UnknownSource(t *testing.T) {
+	_, err := NewConsensusProvider(NewProviderRegistry(), []string{"kraken"}, ConsensusOptions{})
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestConsensusProvider_TwoDivergingQuotesFail(t *testing.T) {
+	now := time.Now()
+	registry := newConsensusRegistry(map[string]domain.LTP{
+		"kraken":   createLTP("BTC/USD", "50000", now),
+		"coinbase": createLTP("BTC/USD", "60000", now),
+	})
+
+	consensus, err := NewConsensusProvider(registry, []string{"kraken", "coinbase"}, ConsensusOptions{
+		MaxDeviation: decimal.RequireFromString("0.02"),
+	})
+	require.NoError(t, err)
+
+	ltp := consensus.Fetch(context.Background(), "BTC/USD")
+	assert.Contains(t, ltp.Error, "insufficient quotes")
+	require.NotNil(t, ltp.Consensus)
+	for _, q := range ltp.Consensus.Quotes {
+		assert.False(t, q.Used, q.Source)
+		assert.Contains(t, q.Reason, "disagree")
+	}
+}
+
+func TestConsensusProvider_VolumeWeightedSkipsQuotesWithoutVolume(t *testing.T) {
+	now := time.Now()
+	registry := newConsensusRegistry(map[string]domain.LTP{
+		"kraken":   withVolume(createLTP("BTC/USD", "50000", now.Add(-10*time.Second)), "3"),
+		"coinbase": withVolume(createLTP("BTC/USD", "50400", now.Add(-5*time.Second)), "1"),
+		"binance":  createLTP("BTC/USD", "50800", now),
+	})
+
+	consensus, err := NewConsensusProvider(registry, []string{"kraken", "coinbase", "binance"}, ConsensusOptions{Method: ConsensusVWAP})
+	require.NoError(t, err)
+
+	ltp := consensus.Fetch(context.Background(), "BTC/USD")
+	require.Empty(t, ltp.Error)
+	assert.Equal(t, "50100", ltp.Amount.String())
+	assert.Equal(t, ConsensusVWAP, ltp.Consensus.Method)
+	assert.True(t, ltp.Timestamp.Equal(now.Add(-5*time.Second)), "timestamp should be the newest used quote")
+	for _, q := range ltp.Consensus.Quotes {
+		if q.Source == "binance" {
+			assert.False(t, q.Used)
+			assert.Equal(t, "no volume reported", q.Reason)
+		} else {
+			assert.True(t, q.Used, q.Source)
+		}
+	}
+}
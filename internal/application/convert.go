@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrInvalidCurrency = errors.New("invalid currency")
+	ErrInvalidAmount   = errors.New("invalid amount")
+)
+
+type Rounding string
+
+const (
+	RoundHalfUp   Rounding = "half_up"
+	RoundHalfEven Rounding = "half_even"
+	RoundDown     Rounding = "down"
+	RoundUp       Rounding = "up"
+)
+
+// Inverse rates are kept to this many decimal places before the result is rounded.
+const rateScale = 16
+
+type CurrencyFormat struct {
+	Precision int32
+	Rounding  Rounding
+}
+
+// ConversionOptions decides how results are rounded; currencies without an entry use Default.
+type ConversionOptions struct {
+	Default    CurrencyFormat
+	Currencies map[string]CurrencyFormat
+}
+
+var defaultCurrencyFormat = CurrencyFormat{Precision: 8, Rounding: RoundHalfEven}
+
+func (s *LTPService) SetConversionOptions(opts ConversionOptions) {
+	if opts.Default.Precision < 0 {
+		opts.Default.Precision = defaultCurrencyFormat.Precision
+	}
+	if opts.Default.Rounding == "" {
+		opts.Default.Rounding = defaultCurrencyFormat.Rounding
+	}
+	currencies := make(map[string]CurrencyFormat, len(opts.Currencies))
+	for currency, format := range opts.Currencies {
+		if format.Rounding == "" {
+			format.Rounding = opts.Default.Rounding
+		}
+		currencies[strings.ToUpper(currency)] = format
+	}
+	opts.Currencies = currencies
+	s.conversion = opts
+}
+
+func (o ConversionOptions) format(currency string) CurrencyFormat {
+	if format, ok := o.Currencies[currency]; ok {
+		return format
+	}
+	if o.Default.Rounding == "" {
+		return defaultCurrencyFormat
+	}
+	return o.Default
+}
+
+func (f CurrencyFormat) round(d decimal.Decimal) decimal.Decimal {
+	switch f.Rounding {
+	case RoundHalfUp:
+		return d.Round(f.Precision)
+	case RoundDown:
+		return d.RoundDown(f.Precision)
+	case RoundUp:
+		return d.RoundUp(f.Precision)
+	default:
+		return d.RoundBank(f.Precision)
+	}
+}
+
+// Convert prices amount of from in to, using the from/to pair or, failing that, the inverse of to/from.
+func (s *LTPService) Convert(ctx context.Context, from, to string, amount decimal.Decimal) (domain.Conversion, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+	if from == "" || to == "" || strings.Contains(from, "/") || strings.Contains(to, "/") {
+		return domain.Conversion{}, fmt.Errorf("%w: from and to must be currency codes", ErrInvalidCurrency)
+	}
+	if amount.IsNegative() {
+		return domain.Conversion{}, fmt.Errorf("%w: must not be negative", ErrInvalidAmount)
+	}
+
+	conv := domain.Conversion{From: from, To: to, Amount: amount}
+	format := s.conversion.format(to)
+	if from == to {
+		conv.Rate = decimal.NewFromInt(1)
+		conv.Result = format.round(amount)
+		conv.Timestamp = time.Now().UTC()
+		return conv, nil
+	}
+
+	ltp, inverted := s.conversionRate(ctx, from, to)
+	if err := ctx.Err(); err != nil {
+		return domain.Conversion{}, fmt.Errorf("%w: %v", domain.ErrCanceled, err)
+	}
+	conv.Pair = ltp.Pair
+	conv.Inverted = inverted
+	conv.Timestamp = ltp.Timestamp
+	conv.Source = ltp.Source
+	if ltp.Error != "" || !ltp.Amount.IsPositive() {
+		conv.Error = ltp.Error
+		if conv.Error == "" {
+			conv.Error = fmt.Sprintf("%s: no price for %s/%s", domain.UnsupportedPairPrefix, from, to)
+		}
+		return conv, nil
+	}
+
+	if inverted {
+		conv.Rate = decimal.NewFromInt(1).DivRound(ltp.Amount, rateScale)
+		conv.Result = format.round(amount.DivRound(ltp.Amount, rateScale))
+	} else {
+		conv.Rate = ltp.Amount
+		conv.Result = format.round(amount.Mul(ltp.Amount))
+	}
+	return conv, nil
+}
+
+// conversionRate prefers whichever direction is already cached, then asks the provider for the direct
+// pair and falls back to the inverse when the direct pair is unsupported.
+func (s *LTPService) conversionRate(ctx context.Context, from, to string) (domain.LTP, bool) {
+	direct := domain.Pair(from + "/" + to)
+	inverse := domain.Pair(to + "/" + from)
+
+	for _, candidate := range []domain.Pair{direct, inverse} {
+		if ltp, ok := s.cache.Get(ctx, candidate); ok && ltp.Error == "" && time.Since(ltp.Timestamp) < s.ttl {
+			return ltp, candidate == inverse
+		}
+	}
+
+	ltp := s.GetLTP(ctx, direct)
+	if !ltp.IsEmpty() && !ltp.IsUnsupported() {
+		return ltp, false
+	}
+	if inv := s.GetLTP(ctx, inverse); !inv.IsEmpty() && !inv.IsUnsupported() {
+		return inv, true
+	}
+	ltp.Pair = direct
+	return ltp, false
+}
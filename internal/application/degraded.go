@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+// SetLastKnownFallback serves a pair's last good price, flagged as stale, when fetching it fails and the
+// cache still holds one younger than maxAge. Zero disables it.
+func (s *LTPService) SetLastKnownFallback(maxAge time.Duration) {
+	s.lastKnown = maxAge
+}
+
+// lastKnownGood returns the cache's last good price for key if degraded mode may serve it.
+func (s *LTPService) lastKnownGood(ctx context.Context, key domain.Pair) (domain.LTP, bool) {
+	if s.lastKnown <= 0 {
+		return domain.LTP{}, false
+	}
+	lastKnownCache, ok := s.cache.(domain.LastKnownCache)
+	if !ok {
+		return domain.LTP{}, false
+	}
+	ltp, ok := lastKnownCache.GetLastKnown(ctx, key)
+	if !ok || time.Since(ltp.Timestamp) >= s.lastKnown {
+		return domain.LTP{}, false
+	}
+	return ltp, true
+}
+
+// failed reports whether ltp carries no price degraded mode could stand in for. Unsupported pairs
+// never had a price, so they are not failures here.
+func failed(ltp domain.LTP) bool {
+	return !ltp.Amount.IsPositive() && !ltp.IsUnsupported()
+}
+
+// degrade replaces a failed result for key with its last known good price when there is one.
+func (s *LTPService) degrade(ctx context.Context, key domain.Pair, ltp domain.LTP) domain.LTP {
+	if !failed(ltp) {
+		return ltp
+	}
+	last, ok := s.lastKnownGood(ctx, key)
+	if !ok {
+		return ltp
+	}
+	age := time.Since(last.Timestamp)
+	log.GetInstance().Warn("Serving last known price for %s from %s ago: %s", key, age.Round(time.Second), ltp.Error)
+	last.Stale = true
+	last.AgeMs = age.Milliseconds()
+	return last
+}
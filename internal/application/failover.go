@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+const FailoverSource = "failover"
+
+type FailoverOptions struct {
+	MaxConsecutiveFailures int
+	ProbeInterval          time.Duration
+	Window                 int
+}
+
+type ProviderHealth struct {
+	Name                string  `json:"name"`
+	Rank                int     `json:"rank"`
+	SuccessRate         float64 `json:"successRate"`
+	AvgLatencyMs        int64   `json:"avgLatencyMs"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	Errors              int     `json:"errors"`
+	Demoted             bool    `json:"demoted"`
+}
+
+// FailoverProvider sends each fetch to the healthiest provider and falls through to the next on failure.
+type FailoverProvider struct {
+	mu      sync.Mutex
+	members []*failoverMember
+	opts    FailoverOptions
+}
+
+type failoverMember struct {
+	name                string
+	provider            MarketDataProvider
+	outcomes            []bool
+	latencies           []time.Duration
+	consecutiveFailures int
+	errors              int
+	demotedUntil        time.Time
+}
+
+func NewFailoverProvider(registry *ProviderRegistry, sources []string, opts FailoverOptions) (*FailoverProvider, error) {
+	if opts.MaxConsecutiveFailures <= 0 {
+		opts.MaxConsecutiveFailures = 3
+	}
+	if opts.ProbeInterval <= 0 {
+		opts.ProbeInterval = 30 * time.Second
+	}
+	if opts.Window <= 0 {
+		opts.Window = 20
+	}
+
+	f := &FailoverProvider{opts: opts}
+	for _, name := range sources {
+		p, ok := registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSource, name)
+		}
+		f.members = append(f.members, &failoverMember{name: name, provider: p})
+	}
+	if len(f.members) == 0 {
+		return nil, fmt.Errorf("failover requires at least one source")
+	}
+	return f, nil
+}
+
+func (f *FailoverProvider) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	var failures []string
+	var lastSource string
+	unsupported := 0
+
+	for _, m := range f.candidates() {
+		start := time.Now()
+		ltp := f.fetchFrom(ctx, m, pair)
+		if ctx.Err() != nil {
+			return ltp
+		}
+
+		// An unlisted pair or our own rate limit says nothing about the exchange's health.
+		if ltp.IsUnsupported() || ltp.IsRateLimited() {
+			failures = append(failures, fmt.Sprintf("%s: %s", m.name, ltp.Error))
+			lastSource = ltp.Source
+			if ltp.IsUnsupported() {
+				unsupported++
+			}
+			continue
+		}
+
+		ok := ltp.Error == "" && ltp.Amount.IsPositive()
+		f.record(m, ok, time.Since(start))
+		if ok {
+			return ltp
+		}
+
+		failures = append(failures, fmt.Sprintf("%s: %s", m.name, ltp.Error))
+		lastSource = ltp.Source
+	}
+
+	msg := fmt.Sprintf("all providers failed: %s", strings.Join(failures, "; "))
+	// When nobody lists the pair, say so in a way callers recognise, so it is not retried like an outage.
+	if unsupported > 0 && unsupported == len(failures) {
+		msg = fmt.Sprintf("%s: no provider lists %s (%s)", domain.UnsupportedPairPrefix, pair, strings.Join(failures, "; "))
+	}
+
+	return domain.LTP{
+		Pair:      pair,
+		Error:     msg,
+		Timestamp: time.Now().UTC(),
+		Source:    lastSource,
+	}
+}
+
+func (f *FailoverProvider) fetchFrom(ctx context.Context, m *failoverMember, pair domain.Pair) (ltp domain.LTP) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.GetInstance().Debug("PANIC in %s Fetch for pair %s: %v", m.name, pair, r)
+			ltp = domain.LTP{Pair: pair, Error: "provider panicked", Timestamp: time.Now().UTC(), Source: m.name}
+		}
+	}()
+	return m.provider.Fetch(ctx, pair)
+}
+
+// candidates orders healthy providers by rank; a demoted provider whose probe is due goes first so it can recover.
+func (f *FailoverProvider) candidates() []*failoverMember {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	var probes, healthy, demoted []*failoverMember
+	for _, m := range f.members {
+		switch {
+		case m.demotedUntil.IsZero():
+			healthy = append(healthy, m)
+		case now.After(m.demotedUntil):
+			probes = append(probes, m)
+		default:
+			demoted = append(demoted, m)
+		}
+	}
+	f.sortByScore(healthy)
+	f.sortByScore(demoted)
+
+	// Demoted providers stay at the back as a last resort rather than being skipped entirely.
+	out := append(probes, healthy...)
+	return append(out, demoted...)
+}
+
+func (f *FailoverProvider) record(m *failoverMember, ok bool, latency time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	m.outcomes = appendWindow(m.outcomes, ok, f.opts.Window)
+	m.latencies = appendWindow(m.latencies, latency, f.opts.Window)
+
+	if ok {
+		if !m.demotedUntil.IsZero() {
+			log.GetInstance().Info("Provider %s recovered, promoting it back", m.name)
+		}
+		m.consecutiveFailures = 0
+		m.demotedUntil = time.Time{}
+		return
+	}
+
+	m.errors++
+	m.consecutiveFailures++
+	if m.consecutiveFailures >= f.opts.MaxConsecutiveFailures {
+		if m.demotedUntil.IsZero() {
+			log.GetInstance().Warn("Provider %s demoted after %d consecutive failures", m.name, m.consecutiveFailures)
+		}
+		m.demotedUntil = time.Now().Add(f.opts.ProbeInterval)
+	}
+}
+
+// Markets lists what any member trades, since any of them may end up answering.
+func (f *FailoverProvider) Markets() []domain.Pair {
+	providers := make([]MarketDataProvider, len(f.members))
+	for i, m := range f.members {
+		providers[i] = m.provider
+	}
+	return unionMarkets(providers)
+}
+
+func (f *FailoverProvider) Health() []ProviderHealth {
+	ranked := f.candidates()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := make([]ProviderHealth, 0, len(ranked))
+	for i, m := range ranked {
+		out = append(out, ProviderHealth{
+			Name:                m.name,
+			Rank:                i + 1,
+			SuccessRate:         m.successRate(),
+			AvgLatencyMs:        m.avgLatency().Milliseconds(),
+			ConsecutiveFailures: m.consecutiveFailures,
+			Errors:              m.errors,
+			Demoted:             !m.demotedUntil.IsZero(),
+		})
+	}
+	return out
+}
+
+func (f *FailoverProvider) sortByScore(members []*failoverMember) {
+	sort.SliceStable(members, func(i, j int) bool {
+		ri, rj := members[i].successRate(), members[j].successRate()
+		if ri != rj {
+			return ri > rj
+		}
+		return members[i].avgLatency() < members[j].avgLatency()
+	})
+}
+
+func (m *failoverMember) successRate() float64 {
+	if len(m.outcomes) == 0 {
+		return 1
+	}
+	ok := 0
+	for _, o := range m.outcomes {
+		if o {
+			ok++
+		}
+	}
+	return float64(ok) / float64(len(m.outcomes))
+}
+
+func (m *failoverMember) avgLatency() time.Duration {
+	if len(m.latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, l := range m.latencies {
+		total += l
+	}
+	return total / time.Duration(len(m.latencies))
+}
+
+func appendWindow[T any](window []T, v T, size int) []T {
+	window = append(window, v)
+	if len(window) > size {
+		window = window[len(window)-size:]
+	}
+	return window
+}
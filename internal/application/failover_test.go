@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverProvider_DemotesAndProbesBack(t *testing.T) {
+	primary := mocks.NewMockMarketDataProvider()
+	secondary := mocks.NewMockMarketDataProvider()
+	registry := NewProviderRegistry()
+	registry.Register("kraken", primary)
+	registry.Register("coinbase", secondary)
+
+	failover, err := NewFailoverProvider(registry, []string{"kraken", "coinbase"}, FailoverOptions{
+		MaxConsecutiveFailures: 1,
+		ProbeInterval:          30 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	pair := domain.Pair("BTC/USD")
+	good := createLTP(pair, "50000", time.Now())
+	primary.SetResponse(pair, domain.LTP{Pair: pair, Error: "kraken down", Timestamp: time.Now()})
+	secondary.SetResponse(pair, good)
+
+	assert.Equal(t, good, failover.Fetch(context.Background(), pair))
+
+	health := failover.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, "coinbase", health[0].Name)
+	assert.Equal(t, "kraken", health[1].Name)
+	assert.True(t, health[1].Demoted)
+	assert.Equal(t, 1, health[1].Errors)
+
+	// While demoted the primary is only a last resort.
+	assert.Equal(t, good, failover.Fetch(context.Background(), pair))
+	assert.Equal(t, 1, primary.GetCallCount(pair))
+
+	recovered := createLTP(pair, "50001", time.Now())
+	primary.SetResponse(pair, recovered)
+	time.Sleep(40 * time.Millisecond)
+
+	assert.Equal(t, recovered, failover.Fetch(context.Background(), pair))
+	assert.False(t, failover.Health()[1].Demoted || failover.Health()[0].Demoted)
+}
+
+func TestFailoverProvider_AllFail(t *testing.T) {
+	p := mocks.NewMockMarketDataProvider()
+	registry := NewProviderRegistry()
+	registry.Register("kraken", p)
+
+	failover, err := NewFailoverProvider(registry, []string{"kraken"}, FailoverOptions{})
+	require.NoError(t, err)
+
+	p.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Error: "boom", Timestamp: time.Now()})
+
+	ltp := failover.Fetch(context.Background(), "BTC/USD")
+	assert.Contains(t, ltp.Error, "all providers failed")
+	assert.Contains(t, ltp.Error, "kraken: boom")
+}
+
+func TestFailoverProvider_UnsupportedPairDoesNotDemote(t *testing.T) {
+	p := mocks.NewMockMarketDataProvider()
+	registry := NewProviderRegistry()
+	registry.Register("bitstamp", p)
+
+	failover, err := NewFailoverProvider(registry, []string{"bitstamp"}, FailoverOptions{MaxConsecutiveFailures: 1})
+	require.NoError(t, err)
+
+	p.SetResponse("ETH/CHF", domain.LTP{Pair: "ETH/CHF", Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()})
+	failover.Fetch(context.Background(), "ETH/CHF")
+
+	health := failover.Health()
+	assert.False(t, health[0].Demoted)
+	assert.Equal(t, 0, health[0].Errors)
+}
+
+func TestFailoverProvider_AllUnsupportedKeepsPrefix(t *testing.T) {
+	kraken := mocks.NewMockMarketDataProvider()
+	bitstamp := mocks.NewMockMarketDataProvider()
+	registry := NewProviderRegistry()
+	registry.Register("kraken", kraken)
+	registry.Register("bitstamp", bitstamp)
+
+	failover, err := NewFailoverProvider(registry, []string{"kraken", "bitstamp"}, FailoverOptions{})
+	require.NoError(t, err)
+
+	pair := domain.Pair("ETH/CHF")
+	unsupported := domain.LTP{Pair: pair, Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()}
+	kraken.SetResponse(pair, unsupported)
+	bitstamp.SetResponse(pair, unsupported)
+
+	ltp := failover.Fetch(context.Background(), pair)
+	assert.True(t, ltp.IsUnsupported(), ltp.Error)
+	assert.Contains(t, ltp.Error, "kraken")
+	assert.Contains(t, ltp.Error, "bitstamp")
+
+	// A real outage on one member means the pair may well exist, so the prefix is dropped.
+	bitstamp.SetResponse(pair, domain.LTP{Pair: pair, Error: "timeout", Timestamp: time.Now()})
+	ltp = failover.Fetch(context.Background(), pair)
+	assert.False(t, ltp.IsUnsupported(), ltp.Error)
+	assert.Contains(t, ltp.Error, "all providers failed")
+}
+
+func TestFailoverProvider_MarketsAreTheUnionOfMembers(t *testing.T) {
+	registry := NewProviderRegistry()
+	registry.Register("kraken", listedMarkets{mocks.NewMockMarketDataProvider(), staticMarkets{"ETH/USD", "BTC/USD"}})
+	registry.Register("bitstamp", listedMarkets{mocks.NewMockMarketDataProvider(), staticMarkets{"BTC/USD", "USD/CHF"}})
+	registry.Register("coinbase", mocks.NewMockMarketDataProvider())
+
+	failover, err := NewFailoverProvider(registry, []string{"kraken", "bitstamp", "coinbase"}, FailoverOptions{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []domain.Pair{"ETH/USD", "BTC/USD", "USD/CHF"}, failover.Markets())
+}
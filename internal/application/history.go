@@ -0,0 +1,30 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var ErrHistoryUnavailable = errors.New("price history not available")
+
+func (s *LTPService) SetHistoryStore(h domain.HistoryStore) {
+	s.history = h
+}
+
+func (s *LTPService) GetHistory(ctx context.Context, pair domain.Pair, from, to time.Time) ([]domain.LTP, error) {
+	if s.history == nil {
+		return nil, ErrHistoryUnavailable
+	}
+	return s.history.Range(ctx, pair, from, to)
+}
+
+// GetLTPAt returns the last price recorded for pair at or before t.
+func (s *LTPService) GetLTPAt(ctx context.Context, pair domain.Pair, t time.Time) (domain.LTP, bool, error) {
+	if s.history == nil {
+		return domain.LTP{}, false, ErrHistoryUnavailable
+	}
+	return s.history.At(ctx, pair, t)
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+// Callers may ask for arbitrary pairs, so the negative cache stops growing past this many live entries.
+const maxNegativeEntries = 10000
+
+// NegativeCacheOptions sets how long failed fetches are remembered. Unsupported pairs stay unsupported
+// until the exchange lists them; anything else is treated as a transient upstream failure.
+type NegativeCacheOptions struct {
+	UnsupportedTTL time.Duration
+	TransientTTL   time.Duration
+}
+
+var defaultNegativeCacheOptions = NegativeCacheOptions{
+	UnsupportedTTL: 5 * time.Minute,
+	TransientTTL:   5 * time.Second,
+}
+
+type negativeEntry struct {
+	ltp       domain.LTP
+	expiresAt time.Time
+}
+
+// negativeCache holds error LTPs apart from the price cache, so a failure never replaces a good price.
+type negativeCache struct {
+	mu      sync.Mutex
+	opts    NegativeCacheOptions
+	entries map[domain.Pair]negativeEntry
+}
+
+func newNegativeCache(opts NegativeCacheOptions) *negativeCache {
+	return &negativeCache{opts: opts, entries: make(map[domain.Pair]negativeEntry)}
+}
+
+func (n *negativeCache) ttl(ltp domain.LTP) time.Duration {
+	if ltp.IsUnsupported() {
+		return n.opts.UnsupportedTTL
+	}
+	return n.opts.TransientTTL
+}
+
+func (n *negativeCache) get(key domain.Pair) (domain.LTP, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	entry, ok := n.entries[key]
+	if !ok {
+		return domain.LTP{}, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(n.entries, key)
+		return domain.LTP{}, false
+	}
+	return entry.ltp, true
+}
+
+func (n *negativeCache) set(key domain.Pair, ltp domain.LTP) {
+	ttl := n.ttl(ltp)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if ttl <= 0 {
+		delete(n.entries, key)
+		return
+	}
+	if _, exists := n.entries[key]; !exists && len(n.entries) >= maxNegativeEntries {
+		n.sweep()
+		if len(n.entries) >= maxNegativeEntries {
+			return
+		}
+	}
+	n.entries[key] = negativeEntry{ltp: ltp, expiresAt: time.Now().Add(ttl)}
+}
+
+// forget drops key once a good price for it arrives.
+func (n *negativeCache) forget(key domain.Pair) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.entries, key)
+}
+
+func (n *negativeCache) sweep() {
+	now := time.Now()
+	for key, entry := range n.entries {
+		if now.After(entry.expiresAt) {
+			delete(n.entries, key)
+		}
+	}
+}
+
+// SetNegativeCache replaces the default TTLs for failed fetches; a zero TTL stops caching that kind.
+func (s *LTPService) SetNegativeCache(opts NegativeCacheOptions) {
+	s.negative = newNegativeCache(opts)
+}
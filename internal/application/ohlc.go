@@ -0,0 +1,75 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var (
+	ErrCandlesUnavailable = errors.New("candles not available")
+	ErrInvalidInterval    = errors.New("invalid interval")
+)
+
+// CandleProvider serves OHLC candles; Intervals lists the supported intervals in minutes.
+type CandleProvider interface {
+	FetchCandles(ctx context.Context, pair domain.Pair, interval int) domain.CandleSeries
+	Intervals() []int
+}
+
+func (s *LTPService) SetCandleProvider(p CandleProvider) {
+	s.candles = p
+}
+
+// GetCandles returns candles for pair at interval opening at or after since (zero for all cached history).
+func (s *LTPService) GetCandles(ctx context.Context, pair domain.Pair, interval int, since time.Time) (domain.CandleSeries, error) {
+	if s.candles == nil {
+		return domain.CandleSeries{}, ErrCandlesUnavailable
+	}
+	if !s.supportsInterval(interval) {
+		return domain.CandleSeries{}, fmt.Errorf("%w: %d, supported intervals are %v", ErrInvalidInterval, interval, s.candles.Intervals())
+	}
+
+	candleCache, _ := s.cache.(domain.CandleCache)
+	if candleCache != nil {
+		if series, ok := candleCache.GetCandles(ctx, pair, interval); ok && time.Since(series.Timestamp) < s.ttl {
+			return series.Since(since), nil
+		}
+	}
+
+	key := fmt.Sprintf("ohlc:%s:%d", pair, interval)
+	res, err := s.shareFetch(ctx, key, func(ctx context.Context) (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.GetInstance().Debug("PANIC in FetchCandles for pair %s: %v", pair, r)
+				err = fmt.Errorf("service temporarily unavailable")
+			}
+		}()
+
+		series := s.candles.FetchCandles(ctx, pair, interval)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+		}
+		if candleCache != nil && series.Error == "" {
+			candleCache.SetCandles(ctx, series)
+		}
+		return series, nil
+	})
+	if err != nil {
+		return domain.CandleSeries{}, err
+	}
+	return res.(domain.CandleSeries).Since(since), nil
+}
+
+func (s *LTPService) supportsInterval(interval int) bool {
+	for _, i := range s.candles.Intervals() {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
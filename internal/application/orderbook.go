@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var (
+	ErrOrderBookUnavailable = errors.New("order book not available")
+	ErrInvalidDepth         = errors.New("invalid depth")
+)
+
+type OrderBookProvider interface {
+	FetchOrderBook(ctx context.Context, pair domain.Pair, depth int) domain.OrderBook
+}
+
+type OrderBookOptions struct {
+	TTL          time.Duration
+	DefaultDepth int
+	MaxDepth     int
+}
+
+// SetOrderBookProvider enables GetOrderBook; books are always fetched at MaxDepth and cut down per request.
+func (s *LTPService) SetOrderBookProvider(p OrderBookProvider, opts OrderBookOptions) {
+	if opts.TTL <= 0 {
+		opts.TTL = 2 * time.Second
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 100
+	}
+	if opts.DefaultDepth <= 0 || opts.DefaultDepth > opts.MaxDepth {
+		opts.DefaultDepth = opts.MaxDepth
+	}
+	s.books = p
+	s.bookOpts = opts
+}
+
+func (s *LTPService) GetOrderBook(ctx context.Context, pair domain.Pair, depth int) (domain.OrderBook, error) {
+	if s.books == nil {
+		return domain.OrderBook{}, ErrOrderBookUnavailable
+	}
+	if depth == 0 {
+		depth = s.bookOpts.DefaultDepth
+	}
+	if depth < 0 || depth > s.bookOpts.MaxDepth {
+		return domain.OrderBook{}, fmt.Errorf("%w: must be between 1 and %d", ErrInvalidDepth, s.bookOpts.MaxDepth)
+	}
+
+	bookCache, _ := s.cache.(domain.OrderBookCache)
+	if bookCache != nil {
+		if book, ok := bookCache.GetOrderBook(ctx, pair); ok && time.Since(book.Timestamp) < s.bookOpts.TTL {
+			return book.Truncate(depth), nil
+		}
+	}
+
+	res, err := s.shareFetch(ctx, "orderbook:"+string(pair), func(ctx context.Context) (result interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.GetInstance().Debug("PANIC in FetchOrderBook for pair %s: %v", pair, r)
+				err = fmt.Errorf("service temporarily unavailable")
+			}
+		}()
+
+		book := s.books.FetchOrderBook(ctx, pair, s.bookOpts.MaxDepth)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+		}
+		if bookCache != nil && book.Error == "" {
+			bookCache.SetOrderBook(ctx, pair, book)
+		}
+		return book, nil
+	})
+	if err != nil {
+		return domain.OrderBook{}, err
+	}
+	return res.(domain.OrderBook).Truncate(depth), nil
+}
@@ -0,0 +1,45 @@
+package application
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+var ErrUnknownSource = errors.New("unknown source")
+
+// ProviderRegistry holds the market data providers the service can be asked to use by name.
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]MarketDataProvider
+}
+
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{
+		providers: make(map[string]MarketDataProvider),
+	}
+}
+
+func (r *ProviderRegistry) Register(name string, p MarketDataProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = p
+}
+
+func (r *ProviderRegistry) Get(name string) (MarketDataProvider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
+
+func (r *ProviderRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -1,8 +1,11 @@
 package application
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/config"
@@ -13,7 +16,12 @@ import (
 )
 
 type MarketDataProvider interface {
-	Fetch(pair domain.Pair) domain.LTP
+	Fetch(ctx context.Context, pair domain.Pair) domain.LTP
+}
+
+// BatchProvider is implemented by providers that can answer several pairs with one upstream request.
+type BatchProvider interface {
+	FetchMany(ctx context.Context, pairs []domain.Pair) []domain.LTP
 }
 
 type HTTPClient interface {
@@ -27,6 +35,25 @@ type LTPService struct {
 	sf         singleflight.Group
 	httpClient HTTPClient
 	baseURL    string
+	registry   *ProviderRegistry
+	source     string
+	tickers    TickerProvider
+	books      OrderBookProvider
+	bookOpts   OrderBookOptions
+	candles    CandleProvider
+	trades     TradeProvider
+	tradeOpts  TradesOptions
+	tradeFeeds map[domain.Pair]*tradeFeed
+	tradeMu    sync.Mutex
+	history    domain.HistoryStore
+	updates    *updateHub
+	conversion ConversionOptions
+	maxStale   time.Duration
+	lastKnown  time.Duration
+	markets    MarketCatalog
+	triOpts    TriangulationOptions
+	negative   *negativeCache
+	onRefresh  func(domain.LTP)
 }
 
 func NewLTPService(c domain.Cache, p MarketDataProvider, ttl time.Duration) *LTPService {
@@ -38,6 +65,8 @@ func NewLTPService(c domain.Cache, p MarketDataProvider, ttl time.Duration) *LTP
 		provider: p,
 		ttl:      ttl,
 		sf:       singleflight.Group{},
+		updates:  newUpdateHub(),
+		negative: newNegativeCache(defaultNegativeCacheOptions),
 	}
 }
 
@@ -62,12 +91,102 @@ func (s *LTPService) GetCache() domain.Cache {
 	return s.cache
 }
 
-func (s *LTPService) GetLTP(pair domain.Pair) domain.LTP {
-	if ltp, ok := s.cache.Get(pair); ok && time.Since(ltp.Timestamp) < s.ttl {
-		return ltp
+// SetProviders makes the registry's providers selectable by name; source names the default provider.
+func (s *LTPService) SetProviders(r *ProviderRegistry, source string) {
+	s.registry = r
+	s.source = source
+}
+
+func (s *LTPService) Sources() []string {
+	if s.registry == nil {
+		return nil
 	}
+	return s.registry.Names()
+}
 
-	val, err, _ := s.sf.Do(string(pair), func() (result interface{}, err error) {
+func (s *LTPService) GetLTP(ctx context.Context, pair domain.Pair) domain.LTP {
+	return s.getLTP(ctx, s.provider, pair, pair, true)
+}
+
+// GetLTPFrom reads the pair from a named provider, caching it apart from the default provider's prices.
+func (s *LTPService) GetLTPFrom(ctx context.Context, source string, pair domain.Pair) (domain.LTP, error) {
+	if source == "" || source == s.source {
+		return s.GetLTP(ctx, pair), nil
+	}
+	provider, err := s.lookupSource(source)
+	if err != nil {
+		return domain.LTP{}, err
+	}
+	return s.getLTP(ctx, provider, sourceKey(source, pair), pair, false), nil
+}
+
+func (s *LTPService) lookupSource(source string) (MarketDataProvider, error) {
+	if s.registry != nil {
+		if provider, ok := s.registry.Get(source); ok {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownSource, source)
+}
+
+func sourceKey(source string, pair domain.Pair) domain.Pair {
+	return domain.Pair(source + ":" + string(pair))
+}
+
+// getLTP serves pair from the cache or the provider; with derive set, unsupported pairs are triangulated.
+func (s *LTPService) getLTP(ctx context.Context, provider MarketDataProvider, key, pair domain.Pair, derive bool) domain.LTP {
+	if ltp, ok := s.cached(ctx, provider, key, pair, derive); ok {
+		return s.degrade(ctx, key, ltp)
+	}
+
+	for {
+		ch := s.sf.DoChan(string(key), s.fetchFunc(ctx, provider, key, pair, derive))
+
+		var res singleflight.Result
+		select {
+		case res = <-ch:
+		case <-ctx.Done():
+			res = singleflight.Result{Err: fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())}
+		}
+
+		// The shared fetch was started by a caller that has since gone away; retry with our own context.
+		if errors.Is(res.Err, domain.ErrCanceled) && ctx.Err() == nil {
+			continue
+		}
+
+		if errors.Is(res.Err, domain.ErrCanceled) {
+			log.GetInstance().Debug("Request for %s canceled: %v", string(pair), res.Err)
+			return domain.LTP{}
+		}
+		if res.Err != nil {
+			log.GetInstance().Warn("Failed to get LTP for %s: %v", string(pair), res.Err)
+			return s.degrade(ctx, key, domain.LTP{})
+		}
+
+		return s.degrade(ctx, key, res.Val.(domain.LTP))
+	}
+}
+
+// shareFetch runs fetch once for concurrent callers of key. The fetch is detached from whoever started it,
+// so one caller going away cannot fail the others; each caller only stops waiting on its own context.
+func (s *LTPService) shareFetch(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ch := s.sf.DoChan(key, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+		defer cancel()
+		return fetch(fetchCtx)
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+	}
+}
+
+// fetchFunc is the singleflight body shared by callers and background revalidation.
+func (s *LTPService) fetchFunc(ctx context.Context, provider MarketDataProvider, key, pair domain.Pair, derive bool) func() (interface{}, error) {
+	return func() (result interface{}, err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				log.GetInstance().Debug("PANIC in provider.Fetch for pair %s: %v", string(pair), r)
@@ -75,23 +194,168 @@ func (s *LTPService) GetLTP(pair domain.Pair) domain.LTP {
 			}
 		}()
 
-		ltp := s.provider.Fetch(pair)
-		s.cache.Set(pair, ltp)
+		// A recent failure is answered again rather than sent upstream.
+		if ltp, ok := s.negative.get(key); ok {
+			return ltp, nil
+		}
+
+		ltp := provider.Fetch(ctx, pair)
+		if derive {
+			ltp = s.derive(ctx, pair, ltp)
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+		}
+		s.store(ctx, key, ltp)
 		return ltp, nil
-	})
+	}
+}
+
+// store caches a fetched price; usable ones from the default provider are also recorded and pushed to subscribers.
+// Failures go to the negative cache so the last good price stays available to readers.
+func (s *LTPService) store(ctx context.Context, key domain.Pair, ltp domain.LTP) {
+	if ltp.Error != "" {
+		s.negative.set(key, ltp)
+		return
+	}
+	s.negative.forget(key)
+	// A price derived from stale legs is stale too, but reads work that out again from its timestamp.
+	cached := ltp
+	cached.Stale, cached.AgeMs = false, 0
+	s.cache.Set(ctx, key, cached)
+	if key != ltp.Pair || ltp.Error != "" || !ltp.Amount.IsPositive() {
+		return
+	}
+	s.record(ctx, ltp)
+}
 
+// record pushes a new price to subscribers and the history store.
+func (s *LTPService) record(ctx context.Context, ltp domain.LTP) {
+	s.updates.publish(ltp)
+	if s.history == nil {
+		return
+	}
+	if err := s.history.Append(ctx, ltp); err != nil {
+		log.GetInstance().Warn("Failed to record history for %s: %v", ltp.Pair, err)
+	}
+}
+
+// Ingest takes a price pushed from outside the fetch path, by the Kraken stream or another replica, as if
+// this one had fetched it. The cache is only written when it holds nothing newer, since replicas sharing
+// Redis may already have it.
+func (s *LTPService) Ingest(ctx context.Context, ltp domain.LTP) {
+	if ltp.Pair == "" || ltp.Error != "" || !ltp.Amount.IsPositive() {
+		return
+	}
+	s.negative.forget(ltp.Pair)
+	if cur, ok := s.cache.Get(ctx, ltp.Pair); !ok || cur.Timestamp.Before(ltp.Timestamp) {
+		s.cache.Set(ctx, ltp.Pair, ltp)
+	}
+	s.record(ctx, ltp)
+}
+
+func (s *LTPService) GetLTPs(ctx context.Context, pairs []domain.Pair) []domain.LTP {
+	return s.getLTPs(ctx, s.provider, nil, pairs)
+}
+
+// GetLTPsFrom is GetLTPs against a named provider; no pairs means every configured pair.
+func (s *LTPService) GetLTPsFrom(ctx context.Context, source string, pairs []domain.Pair) ([]domain.LTP, error) {
+	if source == "" || source == s.source {
+		if len(pairs) == 0 {
+			return s.GetAllLTPs(ctx), nil
+		}
+		return s.GetLTPs(ctx, pairs), nil
+	}
+	provider, err := s.lookupSource(source)
 	if err != nil {
-		log.GetInstance().Warn("Failed to get LTP for %s: %v", string(pair), err)
-		return domain.LTP{}
+		return nil, err
+	}
+	if len(pairs) == 0 {
+		pairs = config.GetInstance().Pairs
+	}
+	return s.getLTPs(ctx, provider, func(pair domain.Pair) domain.Pair { return sourceKey(source, pair) }, pairs), nil
+}
+
+// getLTPs serves what it can from the cache and, when the provider supports it, fetches the misses in one batch.
+// A nil key means the default provider, whose prices are cached by pair and may be triangulated.
+func (s *LTPService) getLTPs(ctx context.Context, provider MarketDataProvider, key func(domain.Pair) domain.Pair, pairs []domain.Pair) []domain.LTP {
+	derive := key == nil
+	if key == nil {
+		key = func(pair domain.Pair) domain.Pair { return pair }
+	}
+	get := func(ctx context.Context, pair domain.Pair) domain.LTP {
+		return s.getLTP(ctx, provider, key(pair), pair, derive)
+	}
+	batcher, ok := provider.(BatchProvider)
+	if !ok {
+		return s.collectLTPs(ctx, pairs, get)
+	}
+
+	fetched := make(map[domain.Pair]domain.LTP)
+	seen := make(map[domain.Pair]bool)
+	var misses []domain.Pair
+	for _, p := range pairs {
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		if ltp, ok := s.cached(ctx, provider, key(p), p, derive); ok {
+			fetched[p] = ltp
+			continue
+		}
+		if ltp, ok := s.negative.get(key(p)); ok {
+			fetched[p] = ltp
+			continue
+		}
+		misses = append(misses, p)
+	}
+
+	if len(misses) > 1 {
+		for i, ltp := range s.fetchMany(ctx, batcher, misses) {
+			if derive {
+				ltp = s.derive(ctx, misses[i], ltp)
+			}
+			s.store(ctx, key(misses[i]), ltp)
+			fetched[misses[i]] = ltp
+		}
 	}
 
-	return val.(domain.LTP)
+	// Anything the batch did not answer goes through the single-pair path.
+	return s.collectLTPs(ctx, pairs, func(ctx context.Context, pair domain.Pair) domain.LTP {
+		if ltp, ok := fetched[pair]; ok {
+			return s.degrade(ctx, key(pair), ltp)
+		}
+		return get(ctx, pair)
+	})
+}
+
+func (s *LTPService) fetchMany(ctx context.Context, batcher BatchProvider, pairs []domain.Pair) (ltps []domain.LTP) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.GetInstance().Debug("PANIC in provider.FetchMany for pairs %v: %v", pairs, r)
+			ltps = nil
+		}
+	}()
+
+	ltps = batcher.FetchMany(ctx, pairs)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if len(ltps) != len(pairs) {
+		log.GetInstance().Warn("Batch fetch returned %d results for %d pairs", len(ltps), len(pairs))
+		return nil
+	}
+	return ltps
 }
 
-func (s *LTPService) GetLTPs(pairs []domain.Pair) []domain.LTP {
+func (s *LTPService) collectLTPs(ctx context.Context, pairs []domain.Pair, get func(context.Context, domain.Pair) domain.LTP) []domain.LTP {
 	var out []domain.LTP
 	for _, p := range pairs {
-		ltp := s.GetLTP(p)
+		if ctx.Err() != nil {
+			log.GetInstance().Debug("Request for pairs %v canceled: %v", pairs, ctx.Err())
+			return nil
+		}
+		ltp := get(ctx, p)
 		if ltp != (domain.LTP{}) {
 			out = append(out, ltp)
 		} else {
@@ -105,25 +369,61 @@ func (s *LTPService) GetLTPs(pairs []domain.Pair) []domain.LTP {
 	return out
 }
 
-func (s *LTPService) GetAllLTPs() []domain.LTP {
+func (s *LTPService) GetAllLTPs(ctx context.Context) []domain.LTP {
 	cfg := config.GetInstance()
-	return s.GetLTPs([]domain.Pair(cfg.Pairs))
+	return s.GetLTPs(ctx, []domain.Pair(cfg.Pairs))
 }
 
-func (s *LTPService) RefreshPairs(pairs []domain.Pair) {
+func (s *LTPService) RefreshPairs(ctx context.Context, pairs []domain.Pair) {
+	if batcher, ok := s.provider.(BatchProvider); ok && len(pairs) > 1 {
+		if ltps := s.fetchMany(ctx, batcher, pairs); ltps != nil {
+			for i, ltp := range ltps {
+				if ltp == (domain.LTP{}) {
+					log.GetInstance().Warn("Cannot refresh and update cache", pairs)
+					continue
+				}
+				s.refreshed(ctx, pairs[i], s.derive(ctx, pairs[i], ltp))
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			log.GetInstance().Debug("Refresh canceled: %v", ctx.Err())
+			return
+		}
+	}
+
 	for _, p := range pairs {
-		ltp := s.provider.Fetch(p)
+		ltp := s.provider.Fetch(ctx, p)
+		if ctx.Err() != nil {
+			log.GetInstance().Debug("Refresh canceled: %v", ctx.Err())
+			return
+		}
 		if ltp == (domain.LTP{}) {
 			log.GetInstance().Warn("Cannot refresh and update cache", pairs)
 			continue
 		}
-		s.cache.Set(p, ltp)
+		s.refreshed(ctx, p, s.derive(ctx, p, ltp))
 	}
 }
 
-func (s *LTPService) ForceRefresh(pair domain.Pair) domain.LTP {
-	ltp := s.provider.Fetch(pair)
-	s.cache.Set(pair, ltp)
+// SetRefreshListener is handed every good price RefreshPairs stores, but not prices fetched for callers.
+func (s *LTPService) SetRefreshListener(fn func(domain.LTP)) {
+	s.onRefresh = fn
+}
+
+func (s *LTPService) refreshed(ctx context.Context, key domain.Pair, ltp domain.LTP) {
+	s.store(ctx, key, ltp)
+	if s.onRefresh != nil && key == ltp.Pair && ltp.Error == "" && ltp.Amount.IsPositive() {
+		s.onRefresh(ltp)
+	}
+}
+
+func (s *LTPService) ForceRefresh(ctx context.Context, pair domain.Pair) domain.LTP {
+	ltp := s.derive(ctx, pair, s.provider.Fetch(ctx, pair))
+	if ctx.Err() != nil {
+		return ltp
+	}
+	s.store(ctx, pair, ltp)
 	return ltp
 }
 
@@ -134,6 +434,21 @@ func (s *LTPService) CheckRedisConnectivity() bool {
 	return s.cache.CheckConnectivity()
 }
 
+// ProviderHealth reports the failover ranking when the service is routed through a FailoverProvider.
+func (s *LTPService) ProviderHealth() []ProviderHealth {
+	if reporter, ok := s.provider.(interface{ Health() []ProviderHealth }); ok {
+		return reporter.Health()
+	}
+	if s.registry != nil {
+		if p, ok := s.registry.Get(FailoverSource); ok {
+			if reporter, ok := p.(interface{ Health() []ProviderHealth }); ok {
+				return reporter.Health()
+			}
+		}
+	}
+	return nil
+}
+
 func (s *LTPService) CheckKrakenConnectivity() bool {
 	client := http.Client{
 		Timeout: 2 * time.Second,
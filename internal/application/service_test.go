@@ -1,11 +1,13 @@
 package application
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -82,16 +84,16 @@ func TestLTPService_GetLTP(t *testing.T) {
 	service := NewLTPService(mockCache, mockProvider, time.Minute)
 
 	pair := domain.Pair("BTC/USD")
-	result := service.GetLTP(pair)
+	result := service.GetLTP(context.Background(), pair)
 	assert.True(t, result.IsEmpty())
 
 	expectedLTP := createLTP(pair, "50000.00", time.Now())
 	mockProvider.SetResponse(pair, expectedLTP)
 
-	result = service.GetLTP(pair)
+	result = service.GetLTP(context.Background(), pair)
 	assert.Equal(t, expectedLTP, result)
 
-	cachedResult, exists := mockCache.Get(pair)
+	cachedResult, exists := mockCache.Get(context.Background(), pair)
 	assert.True(t, exists)
 	assert.Equal(t, expectedLTP, cachedResult)
 }
@@ -111,7 +113,7 @@ func TestLTPService_GetLTPs(t *testing.T) {
 	service := NewLTPService(mockCache, mockProvider, time.Minute)
 	pairs := []domain.Pair{"BTC/USD", "BTC/EUR"}
 
-	results := service.GetLTPs(pairs)
+	results := service.GetLTPs(context.Background(), pairs)
 	assert.Nil(t, results)
 
 	mockLogger.AssertExpectations(t)
@@ -124,7 +126,7 @@ func TestLTPService_GetLTPs(t *testing.T) {
 	mockProvider.SetResponse("BTC/USD", btcLTP)
 	mockProvider.SetResponse("BTC/EUR", btcEurLTP)
 
-	results = service.GetLTPs(pairs)
+	results = service.GetLTPs(context.Background(), pairs)
 	require.Len(t, results, 2)
 	assert.Contains(t, results, btcLTP)
 	assert.Contains(t, results, btcEurLTP)
@@ -141,10 +143,10 @@ func TestLTPService_ForceRefresh(t *testing.T) {
 
 	mockProvider.SetResponse(pair, newLTP)
 
-	result := service.ForceRefresh(pair)
+	result := service.ForceRefresh(context.Background(), pair)
 	assert.Equal(t, newLTP, result)
 
-	cachedResult, exists := mockCache.Get(pair)
+	cachedResult, exists := mockCache.Get(context.Background(), pair)
 	assert.True(t, exists)
 	assert.Equal(t, newLTP, cachedResult)
 }
@@ -160,10 +162,10 @@ func TestLTPService_CacheTTL(t *testing.T) {
 
 	mockProvider.SetResponse(pair, ltp)
 
-	result := service.GetLTP(pair)
+	result := service.GetLTP(context.Background(), pair)
 	assert.Equal(t, ltp, result)
 
-	result = service.GetLTP(pair)
+	result = service.GetLTP(context.Background(), pair)
 	assert.Equal(t, ltp, result)
 
 	time.Sleep(time.Millisecond * 20)
@@ -171,7 +173,7 @@ func TestLTPService_CacheTTL(t *testing.T) {
 	newLTP := createLTP(pair, "51000.00", time.Now())
 	mockProvider.SetResponse(pair, newLTP)
 
-	result = service.GetLTP(pair)
+	result = service.GetLTP(context.Background(), pair)
 	assert.Equal(t, newLTP, result)
 }
 
@@ -227,7 +229,7 @@ func TestGetAllLTPs(t *testing.T) {
 	service := NewLTPService(mockCache, mockProvider, time.Minute)
 
 	mockLogger.On("Warn", mock.Anything, mock.Anything).Return()
-	result := service.GetAllLTPs()
+	result := service.GetAllLTPs(context.Background())
 	assert.Nil(t, result)
 
 	btcLTP := createLTP("BTC/USD", "50000.00", time.Now())
@@ -235,7 +237,7 @@ func TestGetAllLTPs(t *testing.T) {
 	mockProvider.SetResponse("BTC/USD", btcLTP)
 	mockProvider.SetResponse("BTC/EUR", btcEurLTP)
 
-	result = service.GetAllLTPs()
+	result = service.GetAllLTPs(context.Background())
 	require.Len(t, result, 2)
 	assert.Contains(t, result, btcLTP)
 	assert.Contains(t, result, btcEurLTP)
@@ -257,7 +259,7 @@ func TestRefreshPairs(t *testing.T) {
 	mockProvider.SetResponse("BTC/USD", domain.LTP{})
 	mockProvider.SetResponse("BTC/EUR", domain.LTP{})
 
-	service.RefreshPairs(pairs)
+	service.RefreshPairs(context.Background(), pairs)
 
 	mockLogger.AssertCalled(t, "Warn", "Cannot refresh and update cache", mock.Anything)
 
@@ -268,13 +270,13 @@ func TestRefreshPairs(t *testing.T) {
 	mockProvider.SetResponse("BTC/USD", btcLTP)
 	mockProvider.SetResponse("BTC/EUR", btcEurLTP)
 
-	service.RefreshPairs(pairs)
+	service.RefreshPairs(context.Background(), pairs)
 
-	cachedBTC, exists := mockCache.Get("BTC/USD")
+	cachedBTC, exists := mockCache.Get(context.Background(), "BTC/USD")
 	assert.True(t, exists)
 	assert.Equal(t, btcLTP, cachedBTC)
 
-	cachedBTCEUR, exists := mockCache.Get("BTC/EUR")
+	cachedBTCEUR, exists := mockCache.Get(context.Background(), "BTC/EUR")
 	assert.True(t, exists)
 	assert.Equal(t, btcEurLTP, cachedBTCEUR)
 }
@@ -296,7 +298,7 @@ func TestGetLTP_WithPanic(t *testing.T) {
 	mockLogger.On("Debug", "PANIC in provider.Fetch for pair %s: %v", mock.Anything, mock.Anything).Return()
 	mockLogger.On("Warn", "Failed to get LTP for %s: %v", mock.Anything, mock.Anything).Return()
 
-	result := service.GetLTP(pair)
+	result := service.GetLTP(context.Background(), pair)
 	assert.True(t, result.IsEmpty())
 
 	mockLogger.AssertCalled(t, "Debug", "PANIC in provider.Fetch for pair %s: %v", mock.Anything, mock.Anything)
@@ -321,7 +323,7 @@ func TestGetLTP_SingleFlight(t *testing.T) {
 		wg.Add(1)
 		go func(index int) {
 			defer wg.Done()
-			results[index] = service.GetLTP(pair)
+			results[index] = service.GetLTP(context.Background(), pair)
 		}(i)
 	}
 	wg.Wait()
@@ -346,7 +348,7 @@ func TestGetLTPs_EdgeCases(t *testing.T) {
 
 	mockLogger.On("Warn", "Cannot found a LTP for pair %v", mock.Anything).Return()
 
-	result := service.GetLTPs([]domain.Pair{})
+	result := service.GetLTPs(context.Background(), []domain.Pair{})
 	assert.Nil(t, result)
 	mockLogger.AssertCalled(t, "Warn", "Cannot found a LTP for pair %v", mock.Anything)
 
@@ -355,7 +357,7 @@ func TestGetLTPs_EdgeCases(t *testing.T) {
 	mockLogger.On("Warn", "Failed to get LTP for fetch for pair %s", mock.Anything).Return()
 	mockLogger.On("Warn", "Cannot found a LTP for pair %v", mock.Anything).Return()
 
-	result = service.GetLTPs([]domain.Pair{"BTC/USD", "BTC/EUR"})
+	result = service.GetLTPs(context.Background(), []domain.Pair{"BTC/USD", "BTC/EUR"})
 	assert.Nil(t, result)
 	mockLogger.AssertExpectations(t)
 }
@@ -368,10 +370,10 @@ func TestForceRefresh_EmptyLTP(t *testing.T) {
 	pair := domain.Pair("BTC/USD")
 	mockProvider.SetResponse(pair, domain.LTP{})
 
-	result := service.ForceRefresh(pair)
+	result := service.ForceRefresh(context.Background(), pair)
 	assert.True(t, result.IsEmpty())
 
-	cached, exists := mockCache.Get(pair)
+	cached, exists := mockCache.Get(context.Background(), pair)
 	assert.True(t, exists)
 	assert.True(t, cached.IsEmpty())
 }
@@ -482,7 +484,7 @@ func TestLTP_PrecisionInServiceOperations(t *testing.T) {
 	precisionLTP := createLTP("BTC/USD", "0.1", time.Now())
 	mockProvider.SetResponse("BTC/USD", precisionLTP)
 
-	result := service.GetLTP("BTC/USD")
+	result := service.GetLTP(context.Background(), "BTC/USD")
 	assert.True(t, precisionLTP.Amount.Equal(result.Amount),
 		"Accuracy lost during service operation: expected %s, got %s",
 		precisionLTP.Amount.String(), result.Amount.String())
@@ -523,12 +525,12 @@ func TestLTP_ServicePrecisionWithMultipleOperations(t *testing.T) {
 	mockProvider.SetResponse("BTC/USD", ltp)
 
 	for i := 0; i < 10; i++ {
-		result := service.GetLTP("BTC/USD")
+		result := service.GetLTP(context.Background(), "BTC/USD")
 		assert.Equal(t, initialAmount, result.Amount.String(),
 			"Loss of accuracy during operation %d", i+1)
 	}
 
-	refreshed := service.ForceRefresh("BTC/USD")
+	refreshed := service.ForceRefresh(context.Background(), "BTC/USD")
 	assert.Equal(t, initialAmount, refreshed.Amount.String(),
 		"Loss of accuracy during force refresh")
 }
@@ -542,13 +544,13 @@ func TestLTP_PrecisionInCache(t *testing.T) {
 	ltp := createLTP("BTC/USD", precisionAmount, time.Now())
 	mockProvider.SetResponse("BTC/USD", ltp)
 
-	result1 := service.GetLTP("BTC/USD")
+	result1 := service.GetLTP(context.Background(), "BTC/USD")
 	assert.Equal(t, precisionAmount, result1.Amount.String())
 
-	result2 := service.GetLTP("BTC/USD")
+	result2 := service.GetLTP(context.Background(), "BTC/USD")
 	assert.Equal(t, precisionAmount, result2.Amount.String())
 
-	cached, exists := mockCache.Get("BTC/USD")
+	cached, exists := mockCache.Get(context.Background(), "BTC/USD")
 	assert.True(t, exists)
 	assert.Equal(t, precisionAmount, cached.Amount.String())
 }
@@ -571,7 +573,7 @@ func TestGetLTPs_PerPairErrorObjects(t *testing.T) {
 	mockProvider.SetResponse("BTC/EUR", validLTP)
 
 	pairs := []domain.Pair{"BTC/USD", "BTC/EUR"}
-	results := service.GetLTPs(pairs)
+	results := service.GetLTPs(context.Background(), pairs)
 
 	require.NotNil(t, results)
 	require.Len(t, results, 2)
@@ -593,3 +595,838 @@ func TestGetLTPs_PerPairErrorObjects(t *testing.T) {
 	assert.True(t, gotErr, "Did not find BTC/USD in results")
 	assert.True(t, gotValid, "Did not find BTC/EUR in results")
 }
+
+func TestGetLTP_ContextCanceled(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+
+	pair := domain.Pair("BTC/USD")
+	mockProvider.SetDelay(pair, time.Second)
+	mockProvider.SetResponse(pair, createLTP(pair, "50000.00", time.Now()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := service.GetLTP(ctx, pair)
+	assert.True(t, result.IsEmpty())
+
+	_, exists := mockCache.Get(context.Background(), pair)
+	assert.False(t, exists, "canceled fetches must not be cached")
+}
+
+func TestGetLTP_SharedFetchSurvivesCanceledLeader(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+
+	pair := domain.Pair("BTC/USD")
+	expectedLTP := createLTP(pair, "50000.00", time.Now())
+	mockProvider.SetDelay(pair, 50*time.Millisecond)
+	mockProvider.SetResponse(pair, expectedLTP)
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan domain.LTP)
+	go func() {
+		leaderDone <- service.GetLTP(leaderCtx, pair)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	followerDone := make(chan domain.LTP)
+	go func() {
+		followerDone <- service.GetLTP(context.Background(), pair)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	assert.True(t, (<-leaderDone).IsEmpty())
+	assert.Equal(t, expectedLTP, <-followerDone)
+}
+
+func TestGetLTPsFrom_NamedSource(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	krakenProvider := mocks.NewMockMarketDataProvider()
+	coinbaseProvider := mocks.NewMockMarketDataProvider()
+
+	service := NewLTPService(mockCache, krakenProvider, time.Minute)
+	registry := NewProviderRegistry()
+	registry.Register("kraken", krakenProvider)
+	registry.Register("coinbase", coinbaseProvider)
+	service.SetProviders(registry, "kraken")
+
+	krakenLTP := createLTP("BTC/USD", "50000.00", time.Now())
+	coinbaseLTP := createLTP("BTC/USD", "50010.00", time.Now())
+	coinbaseLTP.Source = "coinbase"
+	krakenProvider.SetResponse("BTC/USD", krakenLTP)
+	coinbaseProvider.SetResponse("BTC/USD", coinbaseLTP)
+
+	results, err := service.GetLTPsFrom(context.Background(), "coinbase", []domain.Pair{"BTC/USD"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, coinbaseLTP, results[0])
+
+	results, err = service.GetLTPsFrom(context.Background(), "kraken", []domain.Pair{"BTC/USD"})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, krakenLTP, results[0])
+
+	assert.Equal(t, 1, coinbaseProvider.GetCallCount("BTC/USD"))
+	assert.Equal(t, []string{"coinbase", "kraken"}, service.Sources())
+}
+
+func TestGetLTPsFrom_UnknownSource(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	service.SetProviders(NewProviderRegistry(), "kraken")
+
+	_, err := service.GetLTPsFrom(context.Background(), "bitfinex", []domain.Pair{"BTC/USD"})
+	assert.ErrorIs(t, err, ErrUnknownSource)
+}
+
+func TestGetLTPs_BatchesCacheMisses(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockBatchProvider()
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+
+	cached := createLTP("BTC/USD", "50000.00", time.Now())
+	mockCache.Set(context.Background(), "BTC/USD", cached)
+
+	eurLTP := createLTP("BTC/EUR", "45000.00", time.Now())
+	chfLTP := createLTP("BTC/CHF", "44000.00", time.Now())
+	mockProvider.SetResponse("BTC/EUR", eurLTP)
+	mockProvider.SetResponse("BTC/CHF", chfLTP)
+
+	results := service.GetLTPs(context.Background(), []domain.Pair{"BTC/USD", "BTC/EUR", "BTC/CHF"})
+	assert.Equal(t, []domain.LTP{cached, eurLTP, chfLTP}, results)
+	assert.Equal(t, [][]domain.Pair{{"BTC/EUR", "BTC/CHF"}}, mockProvider.GetBatches())
+	assert.Equal(t, 0, mockProvider.GetCallCount("BTC/EUR"))
+
+	ltp, ok := mockCache.Get(context.Background(), "BTC/CHF")
+	require.True(t, ok)
+	assert.Equal(t, chfLTP, ltp)
+}
+
+func TestRefreshPairs_Batch(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockBatchProvider()
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+
+	mockProvider.SetResponse("BTC/USD", createLTP("BTC/USD", "50000.00", time.Now()))
+	mockProvider.SetResponse("BTC/EUR", createLTP("BTC/EUR", "45000.00", time.Now()))
+
+	service.RefreshPairs(context.Background(), []domain.Pair{"BTC/USD", "BTC/EUR"})
+
+	assert.Len(t, mockProvider.GetBatches(), 1)
+	_, ok := mockCache.Get(context.Background(), "BTC/EUR")
+	assert.True(t, ok)
+}
+
+type stubTickerProvider struct {
+	calls   int
+	tickers map[domain.Pair]domain.Ticker
+}
+
+func (p *stubTickerProvider) FetchTickers(ctx context.Context, pairs []domain.Pair) []domain.Ticker {
+	p.calls++
+	out := make([]domain.Ticker, len(pairs))
+	for i, pair := range pairs {
+		out[i] = p.tickers[pair]
+	}
+	return out
+}
+
+func TestGetTickers_CachesSnapshots(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	service := NewLTPService(mockCache, mocks.NewMockMarketDataProvider(), time.Minute)
+
+	_, err := service.GetTickers(context.Background(), []domain.Pair{"BTC/USD"})
+	assert.ErrorIs(t, err, ErrTickerUnavailable)
+
+	ticker := domain.Ticker{Pair: "BTC/USD", Last: decimal.NewFromInt(50000), Timestamp: time.Now()}
+	provider := &stubTickerProvider{tickers: map[domain.Pair]domain.Ticker{"BTC/USD": ticker}}
+	service.SetTickerProvider(provider)
+
+	for i := 0; i < 2; i++ {
+		tickers, err := service.GetTickers(context.Background(), []domain.Pair{"BTC/USD"})
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Ticker{ticker}, tickers)
+	}
+	assert.Equal(t, 1, provider.calls)
+}
+
+type stubOrderBookProvider struct {
+	calls int
+	depth int
+	book  domain.OrderBook
+}
+
+func (p *stubOrderBookProvider) FetchOrderBook(ctx context.Context, pair domain.Pair, depth int) domain.OrderBook {
+	p.calls++
+	p.depth = depth
+	return p.book
+}
+
+func TestGetOrderBook_CachesAndTruncates(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+
+	level := func(price int64) domain.PriceLevel {
+		return domain.PriceLevel{Price: decimal.NewFromInt(price), Volume: decimal.NewFromInt(1)}
+	}
+	provider := &stubOrderBookProvider{book: domain.OrderBook{
+		Pair:      "BTC/USD",
+		Bids:      []domain.PriceLevel{level(50000), level(49990), level(49980)},
+		Asks:      []domain.PriceLevel{level(50010), level(50020), level(50030)},
+		Timestamp: time.Now(),
+	}}
+	service.SetOrderBookProvider(provider, OrderBookOptions{TTL: time.Minute, DefaultDepth: 2, MaxDepth: 3})
+
+	book, err := service.GetOrderBook(context.Background(), "BTC/USD", 0)
+	require.NoError(t, err)
+	assert.Len(t, book.Bids, 2)
+	assert.Equal(t, 3, provider.depth)
+
+	book, err = service.GetOrderBook(context.Background(), "BTC/USD", 1)
+	require.NoError(t, err)
+	assert.Len(t, book.Asks, 1)
+	assert.Equal(t, 1, provider.calls)
+
+	_, err = service.GetOrderBook(context.Background(), "BTC/USD", 4)
+	assert.ErrorIs(t, err, ErrInvalidDepth)
+}
+
+// slowBookProvider answers after a delay, honouring cancellation like a real HTTP fetch.
+type slowBookProvider struct {
+	calls atomic.Int32
+	delay time.Duration
+	book  domain.OrderBook
+}
+
+func (p *slowBookProvider) FetchOrderBook(ctx context.Context, pair domain.Pair, depth int) domain.OrderBook {
+	p.calls.Add(1)
+	select {
+	case <-time.After(p.delay):
+		return p.book
+	case <-ctx.Done():
+		return domain.OrderBook{Pair: pair, Error: ctx.Err().Error()}
+	}
+}
+
+func TestGetOrderBook_ErrorsAreNotCached(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	provider := &stubOrderBookProvider{book: domain.OrderBook{Pair: "BTC/USD", Error: "timeout", Timestamp: time.Now()}}
+	service.SetOrderBookProvider(provider, OrderBookOptions{TTL: time.Minute})
+
+	for i := 0; i < 2; i++ {
+		book, err := service.GetOrderBook(context.Background(), "BTC/USD", 0)
+		require.NoError(t, err)
+		assert.Equal(t, "timeout", book.Error)
+	}
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestGetOrderBook_SharedFetchSurvivesCanceledLeader(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	provider := &slowBookProvider{delay: 50 * time.Millisecond, book: domain.OrderBook{Pair: "BTC/USD", Timestamp: time.Now()}}
+	service.SetOrderBookProvider(provider, OrderBookOptions{TTL: time.Minute})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := service.GetOrderBook(leaderCtx, "BTC/USD", 0)
+		leaderErr <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	type result struct {
+		book domain.OrderBook
+		err  error
+	}
+	follower := make(chan result)
+	go func() {
+		book, err := service.GetOrderBook(context.Background(), "BTC/USD", 0)
+		follower <- result{book, err}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-leaderErr, domain.ErrCanceled)
+	res := <-follower
+	require.NoError(t, res.err)
+	assert.Empty(t, res.book.Error)
+	assert.Equal(t, int32(1), provider.calls.Load())
+}
+
+type stubCandleProvider struct {
+	calls  int
+	series domain.CandleSeries
+}
+
+func (p *stubCandleProvider) FetchCandles(ctx context.Context, pair domain.Pair, interval int) domain.CandleSeries {
+	p.calls++
+	return p.series
+}
+
+func (p *stubCandleProvider) Intervals() []int {
+	return []int{1, 60}
+}
+
+func TestGetCandles_ValidatesIntervalAndCaches(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+
+	start := time.Unix(1700000000, 0).UTC()
+	provider := &stubCandleProvider{series: domain.CandleSeries{
+		Pair:     "BTC/USD",
+		Interval: 60,
+		Candles: []domain.Candle{
+			{Time: start, Close: decimal.NewFromInt(50000)},
+			{Time: start.Add(time.Hour), Close: decimal.NewFromInt(50100)},
+		},
+		Timestamp: time.Now(),
+	}}
+	service.SetCandleProvider(provider)
+
+	_, err := service.GetCandles(context.Background(), "BTC/USD", 7, time.Time{})
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+
+	series, err := service.GetCandles(context.Background(), "BTC/USD", 60, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, series.Candles, 2)
+
+	series, err = service.GetCandles(context.Background(), "BTC/USD", 60, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, series.Candles, 1)
+	assert.Equal(t, 1, provider.calls)
+}
+
+type stubTradeProvider struct {
+	since   []string
+	batches []domain.TradeBatch
+}
+
+func (p *stubTradeProvider) FetchTrades(ctx context.Context, pair domain.Pair, since string) domain.TradeBatch {
+	p.since = append(p.since, since)
+	batch := p.batches[0]
+	if len(p.batches) > 1 {
+		p.batches = p.batches[1:]
+	}
+	return batch
+}
+
+func TestGetTrades_PollsIncrementallyAndPaginates(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+
+	trade := func(id int64) domain.Trade {
+		return domain.Trade{ID: id, Price: decimal.NewFromInt(50000 + id), Time: time.Unix(id, 0)}
+	}
+	provider := &stubTradeProvider{batches: []domain.TradeBatch{
+		{Pair: "BTC/USD", Trades: []domain.Trade{trade(1), trade(2), trade(3)}, Cursor: "c1"},
+		{Pair: "BTC/USD", Trades: []domain.Trade{trade(3), trade(4)}, Cursor: "c2"},
+	}}
+	service.SetTradeProvider(provider, TradesOptions{TTL: time.Nanosecond, Buffer: 10, DefaultLimit: 2, MaxLimit: 5})
+
+	page, err := service.GetTrades(context.Background(), "BTC/USD", 0, "")
+	require.NoError(t, err)
+	require.Len(t, page.Trades, 2)
+	assert.Equal(t, int64(3), page.Trades[0].ID)
+	assert.Equal(t, "2", page.Cursor)
+
+	page, err = service.GetTrades(context.Background(), "BTC/USD", 5, "")
+	require.NoError(t, err)
+	assert.Len(t, page.Trades, 4, "the overlapping trade must not be duplicated")
+	assert.Empty(t, page.Cursor)
+	assert.Equal(t, []string{"", "c1"}, provider.since)
+
+	page, err = service.GetTrades(context.Background(), "BTC/USD", 5, "3")
+	require.NoError(t, err)
+	require.Len(t, page.Trades, 2)
+	assert.Equal(t, int64(2), page.Trades[0].ID)
+
+	_, err = service.GetTrades(context.Background(), "BTC/USD", 0, "abc")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+type pairTradeProvider struct {
+	supported map[domain.Pair]bool
+}
+
+func (p *pairTradeProvider) FetchTrades(ctx context.Context, pair domain.Pair, since string) domain.TradeBatch {
+	if !p.supported[pair] {
+		return domain.TradeBatch{Pair: pair, Error: domain.UnsupportedPairPrefix + ": " + string(pair)}
+	}
+	return domain.TradeBatch{Pair: pair, Trades: []domain.Trade{{ID: 1, Price: decimal.NewFromInt(1)}}, Cursor: "1"}
+}
+
+func TestGetTrades_FeedsAreBounded(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	provider := &pairTradeProvider{supported: map[domain.Pair]bool{}}
+	service.SetTradeProvider(provider, TradesOptions{TTL: time.Minute})
+
+	batch, err := service.GetTrades(context.Background(), "FOO/BAR", 0, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, batch.Error)
+	assert.Empty(t, service.tradeFeeds, "an unsupported pair must not get a feed")
+
+	for i := 0; i <= maxTradeFeeds; i++ {
+		pair := domain.Pair(fmt.Sprintf("P%d/USD", i))
+		provider.supported[pair] = true
+		_, err := service.GetTrades(context.Background(), pair, 0, "")
+		require.NoError(t, err)
+	}
+	assert.Len(t, service.tradeFeeds, maxTradeFeeds)
+	assert.NotContains(t, service.tradeFeeds, domain.Pair("P0/USD"), "the least recently read feed is evicted")
+}
+
+// blockingTradeProvider answers the first poll at once and holds later ones until release is closed.
+type blockingTradeProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *blockingTradeProvider) FetchTrades(ctx context.Context, pair domain.Pair, since string) domain.TradeBatch {
+	n := p.calls.Add(1)
+	if n > 1 {
+		<-p.release
+	}
+	return domain.TradeBatch{Pair: pair, Trades: []domain.Trade{{ID: int64(n), Price: decimal.NewFromInt(1)}}, Cursor: fmt.Sprint(n)}
+}
+
+func TestGetTrades_PollDoesNotBlockOtherReaders(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	provider := &blockingTradeProvider{release: make(chan struct{})}
+	service.SetTradeProvider(provider, TradesOptions{TTL: 20 * time.Millisecond})
+
+	_, err := service.GetTrades(context.Background(), "BTC/USD", 0, "")
+	require.NoError(t, err)
+	time.Sleep(30 * time.Millisecond)
+
+	polled := make(chan domain.TradeBatch)
+	go func() {
+		batch, _ := service.GetTrades(context.Background(), "BTC/USD", 0, "")
+		polled <- batch
+	}()
+	require.Eventually(t, func() bool { return provider.calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	// A second reader joins the poll in flight and gives up when its own context ends.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = service.GetTrades(ctx, "BTC/USD", 0, "")
+	assert.ErrorIs(t, err, domain.ErrCanceled)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	close(provider.release)
+	batch := <-polled
+	require.Len(t, batch.Trades, 2)
+	assert.Equal(t, int64(2), batch.Trades[0].ID)
+	assert.Equal(t, int32(2), provider.calls.Load(), "concurrent readers share one poll")
+}
+
+type memoryHistory struct {
+	ltps []domain.LTP
+}
+
+func (h *memoryHistory) Append(ctx context.Context, ltp domain.LTP) error {
+	h.ltps = append(h.ltps, ltp)
+	return nil
+}
+
+func (h *memoryHistory) Range(ctx context.Context, pair domain.Pair, from, to time.Time) ([]domain.LTP, error) {
+	return h.ltps, nil
+}
+
+func (h *memoryHistory) At(ctx context.Context, pair domain.Pair, t time.Time) (domain.LTP, bool, error) {
+	return domain.LTP{}, false, nil
+}
+
+func TestRecordsHistoryForFetchedPrices(t *testing.T) {
+	mockProvider := mocks.NewMockMarketDataProvider()
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+
+	_, err := service.GetHistory(context.Background(), "BTC/USD", time.Time{}, time.Now())
+	assert.ErrorIs(t, err, ErrHistoryUnavailable)
+
+	store := &memoryHistory{}
+	service.SetHistoryStore(store)
+
+	good := createLTP("BTC/USD", "50000.00", time.Now())
+	mockProvider.SetResponse("BTC/USD", good)
+	mockProvider.SetResponse("BTC/EUR", domain.LTP{Pair: "BTC/EUR", Error: "timeout", Timestamp: time.Now()})
+
+	service.RefreshPairs(context.Background(), []domain.Pair{"BTC/USD", "BTC/EUR"})
+	service.GetLTP(context.Background(), "BTC/USD")
+
+	assert.Equal(t, []domain.LTP{good}, store.ltps, "errors and cache hits must not be recorded")
+}
+
+func TestConvert_DirectAndInversePairs(t *testing.T) {
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/CHF", createLTP("BTC/CHF", "40000", time.Now()))
+	mockProvider.SetResponse("CHF/BTC", domain.LTP{Pair: "CHF/BTC", Error: domain.UnsupportedPairPrefix + ": CHF/BTC", Timestamp: time.Now()})
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+	service.SetConversionOptions(ConversionOptions{
+		Default: CurrencyFormat{Precision: 8, Rounding: RoundHalfEven},
+		Currencies: map[string]CurrencyFormat{
+			"chf": {Precision: 2, Rounding: RoundDown},
+		},
+	})
+
+	conv, err := service.Convert(context.Background(), "BTC", "chf", decimal.RequireFromString("0.123456"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Pair("BTC/CHF"), conv.Pair)
+	assert.False(t, conv.Inverted)
+	assert.Equal(t, "4938.24", conv.Result.String())
+
+	conv, err = service.Convert(context.Background(), "CHF", "BTC", decimal.NewFromInt(2500))
+	require.NoError(t, err)
+	assert.Equal(t, domain.Pair("BTC/CHF"), conv.Pair)
+	assert.True(t, conv.Inverted)
+	assert.Equal(t, "0.0625", conv.Result.String())
+	assert.Equal(t, "0.000025", conv.Rate.String())
+	assert.Equal(t, 1, mockProvider.GetCallCount("BTC/CHF"), "the inverse lookup must use the cached price")
+
+	conv, err = service.Convert(context.Background(), "ETH", "JPY", decimal.NewFromInt(1))
+	require.NoError(t, err)
+	assert.Contains(t, conv.Error, domain.UnsupportedPairPrefix)
+
+	_, err = service.Convert(context.Background(), "BTC", "CHF", decimal.NewFromInt(-1))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = service.Convert(context.Background(), "BTC/USD", "CHF", decimal.NewFromInt(1))
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+type staticMarkets []domain.Pair
+
+func (m staticMarkets) Markets() []domain.Pair { return m }
+
+func TestGetLTP_TriangulatesUnsupportedPairs(t *testing.T) {
+	mockProvider := mocks.NewMockMarketDataProvider()
+	oldest := time.Now().Add(-10 * time.Second).Truncate(time.Second)
+	mockProvider.SetResponse("ETH/USD", domain.LTP{Pair: "ETH/USD", Amount: decimal.NewFromInt(3000), Timestamp: oldest, Source: "kraken"})
+	mockProvider.SetResponse("USD/CHF", domain.LTP{Pair: "USD/CHF", Amount: decimal.RequireFromString("0.9"), Timestamp: time.Now(), Source: "kraken"})
+	mockProvider.SetResponse("ETH/CHF", domain.LTP{Pair: "ETH/CHF", Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()})
+	mockProvider.SetResponse("CHF/ETH", domain.LTP{Pair: "CHF/ETH", Error: domain.UnsupportedPairPrefix + ": CHF/ETH", Timestamp: time.Now()})
+	mockProvider.SetResponse("CHF/JPY", domain.LTP{Pair: "CHF/JPY", Error: domain.UnsupportedPairPrefix + ": CHF/JPY", Timestamp: time.Now()})
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+
+	assert.True(t, service.GetLTP(context.Background(), "ETH/CHF").IsUnsupported(), "no derivation without a market catalog")
+
+	service = NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+	service.SetMarketCatalog(staticMarkets{"ETH/USD", "USD/CHF", "ETH/EUR", "EUR/GBP"}, TriangulationOptions{Bridges: []string{"USD", "EUR"}})
+
+	ltp := service.GetLTP(context.Background(), "ETH/CHF")
+	require.True(t, ltp.IsDerived(), "got %+v", ltp)
+	assert.Equal(t, "2700", ltp.Amount.String())
+	assert.Equal(t, oldest, ltp.Timestamp, "the oldest leg governs freshness")
+	assert.Equal(t, "kraken", ltp.Source)
+	require.Len(t, ltp.Derived.Legs, 2)
+	assert.Equal(t, domain.Pair("ETH/USD"), ltp.Derived.Legs[0].Pair)
+	assert.Equal(t, domain.Pair("USD/CHF"), ltp.Derived.Legs[1].Pair)
+
+	ltp = service.GetLTP(context.Background(), "CHF/ETH")
+	require.True(t, ltp.IsDerived())
+	assert.True(t, ltp.Derived.Legs[0].Inverted)
+	assert.Equal(t, "0.0003703703703704", ltp.Amount.String())
+
+	assert.True(t, service.GetLTP(context.Background(), "CHF/JPY").IsUnsupported(), "no path leaves the pair unsupported")
+}
+
+func TestGetLTP_TransientLegFailureIsNotUnsupported(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("ETH/CHF", domain.LTP{Pair: "ETH/CHF", Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()})
+	mockProvider.SetResponse("ETH/USD", domain.LTP{Pair: "ETH/USD", Error: "kraken error: status 503", Timestamp: time.Now()})
+	mockProvider.SetResponse("USD/CHF", createLTP("USD/CHF", "0.9", time.Now()))
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+	service.SetNegativeCache(NegativeCacheOptions{UnsupportedTTL: time.Hour})
+	service.SetMarketCatalog(staticMarkets{"ETH/USD", "USD/CHF"}, TriangulationOptions{})
+
+	ltp := service.GetLTP(ctx, "ETH/CHF")
+	assert.False(t, ltp.IsUnsupported(), "got %+v", ltp)
+	assert.Equal(t, "kraken error: status 503", ltp.Error)
+
+	// With no transient TTL the failure is retried, and derives once the leg recovers.
+	mockProvider.SetResponse("ETH/USD", createLTP("ETH/USD", "3000", time.Now()))
+	ltp = service.GetLTP(ctx, "ETH/CHF")
+	require.True(t, ltp.IsDerived(), "got %+v", ltp)
+	assert.Equal(t, "2700", ltp.Amount.String())
+}
+
+func TestGetLTP_TriangulationReportsWorstLegFailure(t *testing.T) {
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("ETH/CHF", domain.LTP{Pair: "ETH/CHF", Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()})
+	mockProvider.SetResponse("ETH/USD", domain.LTP{Pair: "ETH/USD", Error: "kraken error: status 503", Timestamp: time.Now()})
+	mockProvider.SetResponse("USD/CHF", createLTP("USD/CHF", "0.9", time.Now()))
+	mockProvider.SetResponse("ETH/EUR", domain.LTP{Pair: "ETH/EUR", Error: domain.RateLimitedPrefix + ": kraken", Timestamp: time.Now()})
+	mockProvider.SetResponse("EUR/CHF", createLTP("EUR/CHF", "0.95", time.Now()))
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+	service.SetMarketCatalog(staticMarkets{"ETH/USD", "USD/CHF", "ETH/EUR", "EUR/CHF"}, TriangulationOptions{Bridges: []string{"USD", "EUR"}})
+
+	ltp := service.GetLTP(context.Background(), "ETH/CHF")
+	assert.True(t, ltp.IsRateLimited(), "the rate limit on the second path outranks the first path's error, got %+v", ltp)
+}
+
+func TestGetLTP_TriangulationWithStaleLegIsStale(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("ETH/CHF", domain.LTP{Pair: "ETH/CHF", Error: domain.UnsupportedPairPrefix + ": ETH/CHF", Timestamp: time.Now()})
+	mockProvider.SetResponse("ETH/USD", createLTP("ETH/USD", "3000", time.Now()))
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+	service.SetStaleWhileRevalidate(5 * time.Minute)
+	service.SetMarketCatalog(staticMarkets{"ETH/USD", "USD/CHF"}, TriangulationOptions{})
+
+	mockCache.Set(context.Background(), "ETH/USD", createLTP("ETH/USD", "3000", time.Now()))
+	mockCache.Set(context.Background(), "USD/CHF", createLTP("USD/CHF", "0.9", time.Now().Add(-2*time.Minute)))
+
+	ltp := service.GetLTP(context.Background(), "ETH/CHF")
+	require.True(t, ltp.IsDerived(), "got %+v", ltp)
+	assert.True(t, ltp.Stale)
+	assert.InDelta(t, (2 * time.Minute).Milliseconds(), ltp.AgeMs, 1000)
+
+	cached, _ := mockCache.Get(context.Background(), "ETH/CHF")
+	assert.False(t, cached.Stale, "the stale flag must never be cached")
+}
+
+func TestTriangulationOptions_PrefersLiquidBridges(t *testing.T) {
+	g := newMarketGraph([]domain.Pair{"ADA/GBP", "GBP/CHF", "ADA/USD", "USD/CHF"})
+	paths := g.shortestPaths("ADA", "CHF", 3)
+	require.Len(t, paths, 2)
+
+	TriangulationOptions{Bridges: []string{"USD", "EUR"}}.rankPaths(paths)
+	assert.Equal(t, "USD", paths[0][0].to)
+}
+
+// listedMarkets is a provider that publishes a market list alongside a mock Fetch.
+type listedMarkets struct {
+	*mocks.MockMarketDataProvider
+	staticMarkets
+}
+
+func (l listedMarkets) IsSupported(pair domain.Pair) bool {
+	for _, m := range l.staticMarkets {
+		if m == pair {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDerivableCatalog_AcceptsTriangulablePairs(t *testing.T) {
+	catalog := DerivableCatalog{
+		Catalog: listedMarkets{mocks.NewMockMarketDataProvider(), staticMarkets{"ETH/USD", "USD/CHF", "BTC/USD"}},
+		MaxLegs: 2,
+	}
+
+	assert.True(t, catalog.IsSupported("ETH/USD"))
+	assert.True(t, catalog.IsSupported("ETH/CHF"), "two legs through USD")
+	assert.False(t, catalog.IsSupported("ETH/JPY"))
+	assert.False(t, catalog.IsSupported("garbage"))
+}
+
+// gatedProvider blocks every Fetch until release is closed.
+type gatedProvider struct {
+	release chan struct{}
+	calls   chan domain.Pair
+	ltp     domain.LTP
+}
+
+func (g *gatedProvider) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
+	g.calls <- pair
+	<-g.release
+	return g.ltp
+}
+
+func TestGetLTP_StaleWhileRevalidate(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	fresh := createLTP("BTC/USD", "51000", time.Now())
+	provider := &gatedProvider{release: make(chan struct{}), calls: make(chan domain.Pair, 4), ltp: fresh}
+	service := NewLTPService(mockCache, provider, time.Minute)
+	service.SetStaleWhileRevalidate(5 * time.Minute)
+
+	mockCache.Set(context.Background(), "BTC/USD", createLTP("BTC/USD", "50000", time.Now().Add(-2*time.Minute)))
+
+	ltp := service.GetLTP(context.Background(), "BTC/USD")
+	assert.True(t, ltp.Stale)
+	assert.Equal(t, "50000", ltp.Amount.String())
+	assert.InDelta(t, (2 * time.Minute).Milliseconds(), ltp.AgeMs, 1000)
+
+	// A second stale read joins the refresh already in flight rather than starting another.
+	assert.True(t, service.GetLTP(context.Background(), "BTC/USD").Stale)
+	assert.Equal(t, domain.Pair("BTC/USD"), <-provider.calls)
+	close(provider.release)
+
+	require.Eventually(t, func() bool {
+		cached, _ := mockCache.Get(context.Background(), "BTC/USD")
+		return cached.Amount.Equal(fresh.Amount)
+	}, time.Second, 5*time.Millisecond)
+	assert.Empty(t, provider.calls)
+
+	ltp = service.GetLTP(context.Background(), "BTC/USD")
+	assert.False(t, ltp.Stale)
+	assert.Zero(t, ltp.AgeMs)
+
+	cached, _ := mockCache.Get(context.Background(), "BTC/USD")
+	assert.False(t, cached.Stale, "the stale flag must never be cached")
+}
+
+func TestGetLTP_WaitsBeyondMaxStaleness(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/USD", createLTP("BTC/USD", "51000", time.Now()))
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+	service.SetStaleWhileRevalidate(5 * time.Minute)
+
+	mockCache.Set(context.Background(), "BTC/USD", createLTP("BTC/USD", "50000", time.Now().Add(-10*time.Minute)))
+
+	ltp := service.GetLTP(context.Background(), "BTC/USD")
+	assert.False(t, ltp.Stale)
+	assert.Equal(t, "51000", ltp.Amount.String())
+	assert.Equal(t, 1, mockProvider.GetCallCount("BTC/USD"))
+}
+
+func TestGetLTP_ServesLastKnownWhenFetchFails(t *testing.T) {
+	ctx := context.Background()
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Error: "upstream unavailable", Timestamp: time.Now()})
+	mockProvider.SetPanic("BTC/EUR", true)
+	mockProvider.SetResponse("BTC/CHF", domain.LTP{Pair: "BTC/CHF", Error: domain.UnsupportedPairPrefix, Timestamp: time.Now()})
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+	service.SetLastKnownFallback(time.Hour)
+
+	mockCache.Set(ctx, "BTC/USD", createLTP("BTC/USD", "50000", time.Now().Add(-2*time.Minute)))
+	mockCache.Set(ctx, "BTC/EUR", createLTP("BTC/EUR", "45000", time.Now().Add(-2*time.Minute)))
+
+	ltp := service.GetLTP(ctx, "BTC/USD")
+	assert.True(t, ltp.Stale)
+	assert.Empty(t, ltp.Error)
+	assert.Equal(t, "50000", ltp.Amount.String())
+	assert.InDelta(t, (2 * time.Minute).Milliseconds(), ltp.AgeMs, 1000)
+	cached, _ := mockCache.Get(ctx, "BTC/USD")
+	assert.Empty(t, cached.Error, "a failed fetch must not overwrite the last good price")
+
+	ltp = service.GetLTP(ctx, "BTC/EUR")
+	assert.True(t, ltp.Stale)
+	assert.Equal(t, "45000", ltp.Amount.String())
+
+	assert.True(t, service.GetLTP(ctx, "BTC/CHF").IsUnsupported())
+}
+
+func TestGetLTP_LastKnownBeyondMaxAgeIsNotServed(t *testing.T) {
+	ctx := context.Background()
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Error: "upstream unavailable", Timestamp: time.Now()})
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+	service.SetLastKnownFallback(5 * time.Minute)
+
+	mockCache.Set(ctx, "BTC/USD", createLTP("BTC/USD", "50000", time.Now().Add(-10*time.Minute)))
+
+	ltp := service.GetLTP(ctx, "BTC/USD")
+	assert.False(t, ltp.Stale)
+	assert.Equal(t, "upstream unavailable", ltp.Error)
+}
+
+func TestGetLTP_NegativeCacheKeepsGoodPrice(t *testing.T) {
+	ctx := context.Background()
+	mockCache := mocks.NewMockCache()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Error: "upstream unavailable", Timestamp: time.Now()})
+	service := NewLTPService(mockCache, mockProvider, time.Minute)
+	service.SetNegativeCache(NegativeCacheOptions{UnsupportedTTL: time.Hour, TransientTTL: time.Hour})
+
+	good := createLTP("BTC/USD", "50000", time.Now().Add(-2*time.Minute))
+	mockCache.Set(ctx, "BTC/USD", good)
+
+	assert.Equal(t, "upstream unavailable", service.GetLTP(ctx, "BTC/USD").Error)
+	assert.Equal(t, "upstream unavailable", service.GetLTP(ctx, "BTC/USD").Error)
+	assert.Equal(t, 1, mockProvider.GetCallCount("BTC/USD"), "a recent failure must not be retried upstream")
+
+	cached, ok := mockCache.Get(ctx, "BTC/USD")
+	require.True(t, ok)
+	assert.Equal(t, "50000", cached.Amount.String(), "a failure must not replace the cached price")
+
+	// A good refresh clears the failure.
+	mockProvider.SetResponse("BTC/USD", createLTP("BTC/USD", "51000", time.Now()))
+	service.RefreshPairs(ctx, []domain.Pair{"BTC/USD"})
+	assert.Equal(t, "51000", service.GetLTP(ctx, "BTC/USD").Amount.String())
+}
+
+func TestGetLTP_NegativeCacheClassifiesErrors(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := mocks.NewMockMarketDataProvider()
+	mockProvider.SetResponse("BTC/XYZ", domain.LTP{Pair: "BTC/XYZ", Error: domain.UnsupportedPairPrefix + ": BTC/XYZ", Timestamp: time.Now()})
+	mockProvider.SetResponse("BTC/USD", domain.LTP{Pair: "BTC/USD", Error: domain.RateLimitedPrefix, Timestamp: time.Now()})
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+	service.SetNegativeCache(NegativeCacheOptions{UnsupportedTTL: time.Hour})
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, service.GetLTP(ctx, "BTC/XYZ").IsUnsupported())
+		assert.True(t, service.GetLTP(ctx, "BTC/USD").IsRateLimited())
+	}
+	assert.Equal(t, 1, mockProvider.GetCallCount("BTC/XYZ"))
+	assert.Equal(t, 3, mockProvider.GetCallCount("BTC/USD"), "transient failures are not cached with a zero TTL")
+}
+
+func TestGetTickers_SkipsErrorsAndFeedsLTPCache(t *testing.T) {
+	mockCache := mocks.NewMockCache()
+	provider := mocks.NewMockMarketDataProvider()
+	service := NewLTPService(mockCache, provider, time.Minute)
+
+	good := domain.Ticker{Pair: "BTC/USD", Last: decimal.NewFromInt(50000), Timestamp: time.Now()}
+	failed := domain.Ticker{Pair: "ETH/USD", Error: "timeout", Timestamp: time.Now()}
+	tickers := &stubTickerProvider{tickers: map[domain.Pair]domain.Ticker{"BTC/USD": good, "ETH/USD": failed}}
+	service.SetTickerProvider(tickers)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.GetTickers(context.Background(), []domain.Pair{"BTC/USD", "ETH/USD"})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, tickers.calls, "an error ticker must not be served from the cache")
+
+	ltp := service.GetLTP(context.Background(), "BTC/USD")
+	require.Empty(t, ltp.Error)
+	assert.Equal(t, "50000", ltp.Amount.String())
+	assert.Equal(t, 0, provider.GetCallCount("BTC/USD"))
+}
+
+func TestGetCandles_ErrorsAreNotCached(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	provider := &stubCandleProvider{series: domain.CandleSeries{Pair: "BTC/USD", Interval: 60, Error: "timeout", Timestamp: time.Now()}}
+	service.SetCandleProvider(provider)
+
+	for i := 0; i < 2; i++ {
+		series, err := service.GetCandles(context.Background(), "BTC/USD", 60, time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, "timeout", series.Error)
+	}
+	assert.Equal(t, 2, provider.calls)
+}
+
+func TestGetCandles_CanceledCallerDoesNotWait(t *testing.T) {
+	service := NewLTPService(mocks.NewMockCache(), mocks.NewMockMarketDataProvider(), time.Minute)
+	release := make(chan struct{})
+	defer close(release)
+	service.SetCandleProvider(&blockingCandleProvider{release: release})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := service.GetCandles(ctx, "BTC/USD", 60, time.Time{})
+	assert.ErrorIs(t, err, domain.ErrCanceled)
+}
+
+type blockingCandleProvider struct {
+	release chan struct{}
+}
+
+func (p *blockingCandleProvider) FetchCandles(ctx context.Context, pair domain.Pair, interval int) domain.CandleSeries {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+	}
+	return domain.CandleSeries{Pair: pair, Interval: interval, Timestamp: time.Now()}
+}
+
+func (p *blockingCandleProvider) Intervals() []int {
+	return []int{60}
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+// Background refreshes are detached from the request that triggered them, so they get their own deadline.
+const revalidateTimeout = 15 * time.Second
+
+// SetStaleWhileRevalidate lets prices older than the TTL but younger than maxStale be served immediately,
+// flagged as stale, while a refresh runs in the background. Zero disables it.
+func (s *LTPService) SetStaleWhileRevalidate(maxStale time.Duration) {
+	if maxStale > 0 && maxStale <= s.ttl {
+		maxStale = 0
+	}
+	s.maxStale = maxStale
+}
+
+// cached returns a fresh cache entry, or a stale one within maxStale after starting its revalidation.
+func (s *LTPService) cached(ctx context.Context, provider MarketDataProvider, key, pair domain.Pair, derive bool) (domain.LTP, bool) {
+	ltp, ok := s.cache.Get(ctx, key)
+	if !ok {
+		return domain.LTP{}, false
+	}
+	age := time.Since(ltp.Timestamp)
+	if age < s.ttl {
+		return ltp, true
+	}
+	if s.maxStale <= 0 || age >= s.maxStale || ltp.Error != "" {
+		return domain.LTP{}, false
+	}
+
+	s.revalidate(provider, key, pair, derive)
+	ltp.Stale = true
+	ltp.AgeMs = age.Milliseconds()
+	return ltp, true
+}
+
+// revalidate joins or starts the singleflight fetch for key without waiting for it.
+func (s *LTPService) revalidate(provider MarketDataProvider, key, pair domain.Pair, derive bool) {
+	s.sf.DoChan(string(key), func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+		return s.fetchFunc(ctx, provider, key, pair, derive)()
+	})
+}
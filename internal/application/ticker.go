@@ -0,0 +1,99 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/config"
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var ErrTickerUnavailable = errors.New("ticker data not available")
+
+// TickerProvider returns full ticker snapshots for several pairs, in the order requested.
+type TickerProvider interface {
+	FetchTickers(ctx context.Context, pairs []domain.Pair) []domain.Ticker
+}
+
+func (s *LTPService) SetTickerProvider(p TickerProvider) {
+	s.tickers = p
+}
+
+// GetTickers serves fresh snapshots from the cache and fetches the rest in one call; no pairs means every configured pair.
+func (s *LTPService) GetTickers(ctx context.Context, pairs []domain.Pair) ([]domain.Ticker, error) {
+	if s.tickers == nil {
+		return nil, ErrTickerUnavailable
+	}
+	if len(pairs) == 0 {
+		pairs = config.GetInstance().Pairs
+	}
+
+	tickerCache, _ := s.cache.(domain.TickerCache)
+
+	out := make([]domain.Ticker, len(pairs))
+	var misses []domain.Pair
+	var missIdx []int
+	for i, p := range pairs {
+		if tickerCache != nil {
+			if t, ok := tickerCache.GetTicker(ctx, p); ok && time.Since(t.Timestamp) < s.ttl {
+				out[i] = t
+				continue
+			}
+		}
+		misses = append(misses, p)
+		missIdx = append(missIdx, i)
+	}
+	if len(misses) == 0 {
+		return out, nil
+	}
+
+	fetched, err := s.fetchTickers(ctx, misses)
+	if err != nil {
+		return nil, err
+	}
+	for j, t := range fetched {
+		out[missIdx[j]] = t
+		if t.Error != "" {
+			continue
+		}
+		if tickerCache != nil {
+			tickerCache.SetTicker(ctx, misses[j], t)
+		}
+		s.ingestTickerLast(ctx, t)
+	}
+	return out, nil
+}
+
+// ingestTickerLast feeds the ticker's last trade to the LTP cache, unless the default LTPs come from another source.
+func (s *LTPService) ingestTickerLast(ctx context.Context, t domain.Ticker) {
+	if s.source != "" && t.Source != "" && t.Source != s.source {
+		return
+	}
+	s.Ingest(ctx, domain.LTP{
+		Pair:      t.Pair,
+		Amount:    t.Last,
+		Timestamp: t.Timestamp,
+		Source:    t.Source,
+	})
+}
+
+func (s *LTPService) fetchTickers(ctx context.Context, pairs []domain.Pair) (tickers []domain.Ticker, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.GetInstance().Debug("PANIC in FetchTickers for pairs %v: %v", pairs, r)
+			tickers, err = nil, fmt.Errorf("service temporarily unavailable")
+		}
+	}()
+
+	tickers = s.tickers.FetchTickers(ctx, pairs)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+	}
+	if len(tickers) != len(pairs) {
+		return nil, fmt.Errorf("ticker provider returned %d results for %d pairs", len(tickers), len(pairs))
+	}
+	return tickers, nil
+}
@@ -0,0 +1,248 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var (
+	ErrTradesUnavailable = errors.New("trades not available")
+	ErrInvalidLimit      = errors.New("invalid limit")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+)
+
+// TradeProvider returns trades newer than the since cursor, oldest first, along with the cursor to resume from.
+type TradeProvider interface {
+	FetchTrades(ctx context.Context, pair domain.Pair, since string) domain.TradeBatch
+}
+
+type TradesOptions struct {
+	TTL          time.Duration
+	Buffer       int
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Feeds are only created for pairs the upstream answered for; past this many the least recently read one goes.
+const maxTradeFeeds = 256
+
+// tradeFeed keeps the most recent trades for a pair and the upstream cursor to poll from.
+type tradeFeed struct {
+	mu        sync.Mutex
+	trades    []domain.Trade
+	cursor    string
+	fetchedAt time.Time
+	source    string
+
+	// readAt is guarded by LTPService.tradeMu.
+	readAt time.Time
+}
+
+func (s *LTPService) SetTradeProvider(p TradeProvider, opts TradesOptions) {
+	if opts.TTL <= 0 {
+		opts.TTL = 5 * time.Second
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = 1000
+	}
+	if opts.MaxLimit <= 0 || opts.MaxLimit > opts.Buffer {
+		opts.MaxLimit = opts.Buffer
+	}
+	if opts.DefaultLimit <= 0 || opts.DefaultLimit > opts.MaxLimit {
+		opts.DefaultLimit = opts.MaxLimit
+	}
+
+	s.tradeMu.Lock()
+	defer s.tradeMu.Unlock()
+	s.trades = p
+	s.tradeOpts = opts
+	s.tradeFeeds = make(map[domain.Pair]*tradeFeed)
+}
+
+// GetTrades returns up to limit trades, newest first, older than cursor; the result's Cursor pages further back.
+func (s *LTPService) GetTrades(ctx context.Context, pair domain.Pair, limit int, cursor string) (domain.TradeBatch, error) {
+	if s.trades == nil {
+		return domain.TradeBatch{}, ErrTradesUnavailable
+	}
+	if limit == 0 {
+		limit = s.tradeOpts.DefaultLimit
+	}
+	if limit < 0 || limit > s.tradeOpts.MaxLimit {
+		return domain.TradeBatch{}, fmt.Errorf("%w: must be between 1 and %d", ErrInvalidLimit, s.tradeOpts.MaxLimit)
+	}
+	var before int64
+	if cursor != "" {
+		id, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil {
+			return domain.TradeBatch{}, fmt.Errorf("%w: %s", ErrInvalidCursor, cursor)
+		}
+		before = id
+	}
+
+	feed, ok := s.tradeFeed(pair)
+	if !ok {
+		// Poll before creating the feed, so unknown or failing pairs never take a slot.
+		res, err := s.shareFetch(ctx, "trades:"+string(pair), func(ctx context.Context) (interface{}, error) {
+			return s.pollTrades(ctx, pair, "")
+		})
+		if err != nil {
+			return domain.TradeBatch{}, err
+		}
+		batch := res.(domain.TradeBatch)
+		if batch.Error != "" {
+			return batch, nil
+		}
+		feed = s.addTradeFeed(pair, batch)
+	} else if batch, err := s.refreshTradeFeed(ctx, pair, feed); err != nil {
+		return domain.TradeBatch{}, err
+	} else if batch.Error != "" {
+		feed.mu.Lock()
+		empty := len(feed.trades) == 0
+		feed.mu.Unlock()
+		if empty {
+			return batch, nil
+		}
+	}
+
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	page := make([]domain.Trade, 0, limit)
+	next := ""
+	for i := len(feed.trades) - 1; i >= 0; i-- {
+		t := feed.trades[i]
+		if before != 0 && t.ID >= before {
+			continue
+		}
+		if len(page) == limit {
+			next = strconv.FormatInt(page[len(page)-1].ID, 10)
+			break
+		}
+		page = append(page, t)
+	}
+
+	return domain.TradeBatch{
+		Pair:      pair,
+		Trades:    page,
+		Cursor:    next,
+		Timestamp: feed.fetchedAt,
+		Source:    feed.source,
+	}, nil
+}
+
+func (s *LTPService) tradeFeed(pair domain.Pair) (*tradeFeed, bool) {
+	s.tradeMu.Lock()
+	defer s.tradeMu.Unlock()
+	feed, ok := s.tradeFeeds[pair]
+	if ok {
+		feed.readAt = time.Now()
+	}
+	return feed, ok
+}
+
+// addTradeFeed stores a first batch for pair, joining a feed a concurrent request may have just created.
+func (s *LTPService) addTradeFeed(pair domain.Pair, batch domain.TradeBatch) *tradeFeed {
+	s.tradeMu.Lock()
+	feed, ok := s.tradeFeeds[pair]
+	if !ok {
+		if len(s.tradeFeeds) >= maxTradeFeeds {
+			s.evictTradeFeed()
+		}
+		feed = &tradeFeed{}
+		s.tradeFeeds[pair] = feed
+	}
+	feed.readAt = time.Now()
+	s.tradeMu.Unlock()
+
+	feed.mu.Lock()
+	defer feed.mu.Unlock()
+	feed.append(batch, s.tradeOpts.Buffer)
+	return feed
+}
+
+// evictTradeFeed drops the feed read longest ago; callers hold tradeMu.
+func (s *LTPService) evictTradeFeed() {
+	var idle domain.Pair
+	var idleAt time.Time
+	for pair, feed := range s.tradeFeeds {
+		if idle == "" || feed.readAt.Before(idleAt) {
+			idle, idleAt = pair, feed.readAt
+		}
+	}
+	delete(s.tradeFeeds, idle)
+}
+
+// refreshTradeFeed polls for trades newer than the feed once its TTL has passed. Callers share one upstream
+// call and wait for it only as long as their own context allows; the feed is locked just to merge the batch.
+func (s *LTPService) refreshTradeFeed(ctx context.Context, pair domain.Pair, feed *tradeFeed) (domain.TradeBatch, error) {
+	feed.mu.Lock()
+	fresh := time.Since(feed.fetchedAt) < s.tradeOpts.TTL
+	cursor := feed.cursor
+	feed.mu.Unlock()
+	if fresh {
+		return domain.TradeBatch{}, nil
+	}
+
+	res, err := s.shareFetch(ctx, "trades:"+string(pair), func(ctx context.Context) (interface{}, error) {
+		batch, err := s.pollTrades(ctx, pair, cursor)
+		if err != nil {
+			return nil, err
+		}
+		feed.mu.Lock()
+		defer feed.mu.Unlock()
+		if batch.Error != "" {
+			if len(feed.trades) > 0 {
+				log.GetInstance().Warn("Failed to poll trades for %s, serving buffered trades: %s", pair, batch.Error)
+			}
+		} else {
+			feed.append(batch, s.tradeOpts.Buffer)
+		}
+		return batch, nil
+	})
+	if err != nil {
+		return domain.TradeBatch{}, err
+	}
+	return res.(domain.TradeBatch), nil
+}
+
+func (s *LTPService) pollTrades(ctx context.Context, pair domain.Pair, since string) (batch domain.TradeBatch, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.GetInstance().Debug("PANIC in FetchTrades for pair %s: %v", pair, r)
+			err = fmt.Errorf("service temporarily unavailable")
+		}
+	}()
+
+	batch = s.trades.FetchTrades(ctx, pair, since)
+	if ctx.Err() != nil {
+		return domain.TradeBatch{}, fmt.Errorf("%w: %v", domain.ErrCanceled, ctx.Err())
+	}
+	return batch, nil
+}
+
+func (f *tradeFeed) append(batch domain.TradeBatch, buffer int) {
+	var lastID int64
+	if n := len(f.trades); n > 0 {
+		lastID = f.trades[n-1].ID
+	}
+	// Kraken's since cursor is inclusive, so the last trade of the previous poll can come back.
+	for _, t := range batch.Trades {
+		if t.ID > lastID {
+			f.trades = append(f.trades, t)
+		}
+	}
+	if len(f.trades) > buffer {
+		f.trades = append([]domain.Trade(nil), f.trades[len(f.trades)-buffer:]...)
+	}
+	if batch.Cursor != "" {
+		f.cursor = batch.Cursor
+	}
+	f.fetchedAt = time.Now()
+	f.source = batch.Source
+}
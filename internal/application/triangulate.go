@@ -0,0 +1,274 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// Bound the number of equally short paths tried before giving up on a pair.
+const maxTriangulationPaths = 16
+
+// MarketCatalog lists the pairs a provider trades directly, as BASE/QUOTE.
+type MarketCatalog interface {
+	Markets() []domain.Pair
+}
+
+// unionMarkets lists every market any of providers trades; providers without a catalog add nothing.
+func unionMarkets(providers []MarketDataProvider) []domain.Pair {
+	seen := make(map[domain.Pair]bool)
+	var out []domain.Pair
+	for _, p := range providers {
+		c, ok := p.(MarketCatalog)
+		if !ok {
+			continue
+		}
+		for _, m := range c.Markets() {
+			if !seen[m] {
+				seen[m] = true
+				out = append(out, m)
+			}
+		}
+	}
+	return out
+}
+
+// DerivableCatalog accepts the pairs Catalog serves directly and those it can triangulate within MaxLegs
+// markets, so pairs that only exist as cross rates pass startup validation.
+type DerivableCatalog struct {
+	Catalog interface {
+		MarketCatalog
+		IsSupported(pair domain.Pair) bool
+	}
+	MaxLegs int
+}
+
+func (d DerivableCatalog) IsSupported(pair domain.Pair) bool {
+	if d.Catalog.IsSupported(pair) {
+		return true
+	}
+	base, quote, ok := splitPair(pair)
+	if !ok {
+		return false
+	}
+	return len(newMarketGraph(d.Catalog.Markets()).shortestPaths(base, quote, d.MaxLegs)) > 0
+}
+
+// TriangulationOptions bounds path length; Bridges ranks intermediate currencies by liquidity, most liquid first.
+type TriangulationOptions struct {
+	MaxLegs int
+	Bridges []string
+}
+
+// SetMarketCatalog lets prices for pairs the default provider does not list be derived through intermediate markets.
+func (s *LTPService) SetMarketCatalog(c MarketCatalog, opts TriangulationOptions) {
+	if opts.MaxLegs < 2 {
+		opts.MaxLegs = 3
+	}
+	bridges := make([]string, 0, len(opts.Bridges))
+	for _, b := range opts.Bridges {
+		bridges = append(bridges, strings.ToUpper(strings.TrimSpace(b)))
+	}
+	opts.Bridges = bridges
+	s.markets = c
+	s.triOpts = opts
+}
+
+type marketEdge struct {
+	to     string
+	pair   domain.Pair
+	invert bool
+}
+
+type marketGraph map[string][]marketEdge
+
+func newMarketGraph(markets []domain.Pair) marketGraph {
+	g := make(marketGraph)
+	for _, m := range markets {
+		base, quote, ok := splitPair(m)
+		if !ok {
+			continue
+		}
+		g[base] = append(g[base], marketEdge{to: quote, pair: m})
+		g[quote] = append(g[quote], marketEdge{to: base, pair: m, invert: true})
+	}
+	return g
+}
+
+func splitPair(pair domain.Pair) (string, string, bool) {
+	base, quote, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(string(pair))), "/")
+	if !ok || base == "" || quote == "" || base == quote {
+		return "", "", false
+	}
+	return base, quote, true
+}
+
+// shortestPaths returns every path with the fewest legs from base to quote, up to maxTriangulationPaths.
+func (g marketGraph) shortestPaths(base, quote string, maxLegs int) [][]marketEdge {
+	type partial struct {
+		at   string
+		legs []marketEdge
+	}
+	// depth records the level a currency was first reached at; revisiting it at that same level
+	// keeps alternative paths of equal length, anything later is longer.
+	depth := map[string]int{base: 0}
+	frontier := []partial{{at: base}}
+	var found [][]marketEdge
+
+	for level := 1; level <= maxLegs && len(found) == 0 && len(frontier) > 0; level++ {
+		var next []partial
+		for _, p := range frontier {
+			for _, e := range g[p.at] {
+				if d, seen := depth[e.to]; seen && d < level {
+					continue
+				}
+				legs := append(append([]marketEdge(nil), p.legs...), e)
+				if e.to == quote {
+					if len(found) < maxTriangulationPaths {
+						found = append(found, legs)
+					}
+					continue
+				}
+				depth[e.to] = level
+				next = append(next, partial{at: e.to, legs: legs})
+			}
+		}
+		frontier = next
+	}
+	return found
+}
+
+// rankPaths orders paths so those going through the most liquid bridge currencies are tried first.
+func (o TriangulationOptions) rankPaths(paths [][]marketEdge) {
+	rank := func(path []marketEdge) int {
+		score := 0
+		for _, e := range path[:len(path)-1] {
+			r := len(o.Bridges)
+			for i, b := range o.Bridges {
+				if b == e.to {
+					r = i
+					break
+				}
+			}
+			score += r
+		}
+		return score
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return rank(paths[i]) < rank(paths[j]) })
+}
+
+// derive replaces an unsupported price with a cross rate when a market catalog is configured.
+// If no path could be priced because a leg failed transiently, the pair takes that leg's error
+// rather than staying unsupported, so the negative cache retries it soon.
+func (s *LTPService) derive(ctx context.Context, pair domain.Pair, ltp domain.LTP) domain.LTP {
+	if s.markets == nil || !ltp.IsUnsupported() {
+		return ltp
+	}
+	derived, ok := s.triangulate(ctx, pair)
+	if ok {
+		return derived
+	}
+	if derived.Error != "" {
+		return domain.LTP{Pair: pair, Error: derived.Error, Timestamp: derived.Timestamp, Source: derived.Source}
+	}
+	return ltp
+}
+
+// triangulate prices pair through the best-ranked shortest path whose legs all have prices.
+// The result carries the oldest leg's timestamp so the cache expires it with its stalest input.
+// When every path fails, it returns the worst transient leg failure, if any: see failureRank.
+func (s *LTPService) triangulate(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	base, quote, ok := splitPair(pair)
+	if !ok {
+		return domain.LTP{}, false
+	}
+	paths := newMarketGraph(s.markets.Markets()).shortestPaths(base, quote, s.triOpts.MaxLegs)
+	s.triOpts.rankPaths(paths)
+
+	var failure domain.LTP
+	for _, path := range paths {
+		if len(path) < 2 {
+			continue
+		}
+		ltp, ok := s.pricePath(ctx, pair, path)
+		if ok {
+			return ltp, true
+		}
+		if failureRank(ltp) > failureRank(failure) {
+			failure = ltp
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return failure, false
+}
+
+// failureRank orders leg failures for triangulate. A rate limit outranks other upstream errors because
+// retrying the other paths soon would hit the same limit; unsupported legs and successes rank lowest.
+// Equal ranks keep the earlier, better-ranked path's failure.
+func failureRank(ltp domain.LTP) int {
+	switch {
+	case ltp.Error == "" || ltp.IsUnsupported():
+		return 0
+	case ltp.IsRateLimited():
+		return 2
+	default:
+		return 1
+	}
+}
+
+// pricePath multiplies out path's legs; on failure it returns the leg that could not be priced.
+// A stale leg makes the derived price stale, aged by its oldest stale leg.
+func (s *LTPService) pricePath(ctx context.Context, pair domain.Pair, path []marketEdge) (domain.LTP, bool) {
+	rate := decimal.NewFromInt(1)
+	legs := make([]domain.Leg, 0, len(path))
+	var oldest time.Time
+	var stale bool
+	var ageMs int64
+	sources := make(map[string]bool)
+
+	for _, e := range path {
+		ltp := s.getLTP(ctx, s.provider, e.pair, e.pair, false)
+		if ltp.Error != "" || !ltp.Amount.IsPositive() {
+			return ltp, false
+		}
+		if e.invert {
+			rate = rate.DivRound(ltp.Amount, rateScale)
+		} else {
+			rate = rate.Mul(ltp.Amount)
+		}
+		if oldest.IsZero() || ltp.Timestamp.Before(oldest) {
+			oldest = ltp.Timestamp
+		}
+		sources[ltp.Source] = true
+		if ltp.Stale {
+			stale = true
+			ageMs = max(ageMs, ltp.AgeMs)
+		}
+		legs = append(legs, domain.Leg{
+			Pair:      e.pair,
+			Amount:    ltp.Amount,
+			Inverted:  e.invert,
+			Timestamp: ltp.Timestamp,
+			Source:    ltp.Source,
+		})
+	}
+
+	derived := domain.LTP{
+		Pair:      pair,
+		Amount:    rate.Round(rateScale),
+		Timestamp: oldest,
+		Derived:   &domain.Derivation{Legs: legs},
+		Stale:     stale,
+		AgeMs:     ageMs,
+	}
+	if len(sources) == 1 {
+		derived.Source = legs[0].Source
+	}
+	return derived, true
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/adapters/log"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+var ErrInvalidEventID = errors.New("invalid event id")
+
+const (
+	subscriberBuffer = 64
+	replayBuffer     = 256
+)
+
+// PriceEvent is a price update numbered so a reconnecting client can resume after the last one it saw.
+type PriceEvent struct {
+	ID  uint64
+	LTP domain.LTP
+}
+
+// Subscription delivers updates for a set of pairs. Events is closed if the subscriber falls behind.
+// Reset is set when the requested resume point is no longer buffered, so some updates after it are
+// lost and the client has to resync instead of trusting the replay.
+type Subscription struct {
+	Events <-chan PriceEvent
+	Reset  bool
+
+	hub     *updateHub
+	ch      chan PriceEvent
+	pairs   map[domain.Pair]bool
+	dropped bool
+}
+
+// Dropped reports whether the subscription was closed because the subscriber was too slow.
+func (sub *Subscription) Dropped() bool {
+	sub.hub.mu.Lock()
+	defer sub.hub.mu.Unlock()
+	return sub.dropped
+}
+
+func (sub *Subscription) wants(pair domain.Pair) bool {
+	return len(sub.pairs) == 0 || sub.pairs[pair]
+}
+
+// updateHub fans price updates out to subscribers without ever blocking the publisher.
+// Event IDs restart with the process; epoch tells one process's numbering from another's.
+type updateHub struct {
+	mu     sync.Mutex
+	epoch  string
+	nextID uint64
+	subs   map[*Subscription]struct{}
+	recent []PriceEvent
+}
+
+func newUpdateHub() *updateHub {
+	return &updateHub{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+func (h *updateHub) publish(ltp domain.LTP) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	ev := PriceEvent{ID: h.nextID, LTP: ltp}
+	h.recent = appendWindow(h.recent, ev, replayBuffer)
+
+	for sub := range h.subs {
+		if !sub.wants(ltp.Pair) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			// A slow client must not hold up the refresher; it can reconnect and resume from its last event.
+			log.GetInstance().Warn("Dropping price subscriber that fell %d events behind", cap(sub.ch))
+			h.drop(sub)
+		}
+	}
+}
+
+func (h *updateHub) subscribe(pairs []domain.Pair, lastEventID uint64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{hub: h, pairs: make(map[domain.Pair]bool, len(pairs))}
+	for _, p := range pairs {
+		sub.pairs[p] = true
+	}
+
+	var replay []PriceEvent
+	switch {
+	case lastEventID == 0:
+	case lastEventID > h.nextID || (len(h.recent) > 0 && lastEventID+1 < h.recent[0].ID):
+		sub.Reset = true
+	default:
+		for _, ev := range h.recent {
+			if ev.ID > lastEventID && sub.wants(ev.LTP.Pair) {
+				replay = append(replay, ev)
+			}
+		}
+	}
+
+	sub.ch = make(chan PriceEvent, subscriberBuffer+len(replay))
+	for _, ev := range replay {
+		sub.ch <- ev
+	}
+	sub.Events = sub.ch
+	h.subs[sub] = struct{}{}
+	return sub
+}
+
+func (h *updateHub) unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (h *updateHub) drop(sub *Subscription) {
+	sub.dropped = true
+	delete(h.subs, sub)
+	close(sub.ch)
+}
+
+// Subscribe streams price updates for pairs (all pairs when empty), first replaying buffered events after lastEventID.
+func (s *LTPService) Subscribe(pairs []domain.Pair, lastEventID uint64) *Subscription {
+	return s.updates.subscribe(pairs, lastEventID)
+}
+
+// UpdatesEpoch identifies this process's event numbering. An event ID is only meaningful for Subscribe
+// together with the epoch it was issued in.
+func (s *LTPService) UpdatesEpoch() string {
+	return s.updates.epoch
+}
+
+// EventID is the resume token for an event, "<epoch>-<n>".
+func (s *LTPService) EventID(id uint64) string {
+	return s.updates.epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// ParseEventID reads a token from EventID for Subscribe. A token from another epoch, including a bare number
+// from before tokens carried one, cannot be resumed from and asks for a reset instead.
+func (s *LTPService) ParseEventID(v string) (id uint64, reset bool, err error) {
+	if v == "" {
+		return 0, false, nil
+	}
+	epoch, seq := "", v
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		epoch, seq = v[:i], v[i+1:]
+	}
+	id, err = strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return 0, false, ErrInvalidEventID
+	}
+	if epoch != s.updates.epoch {
+		return 0, true, nil
+	}
+	return id, false, nil
+}
+
+func (s *LTPService) Unsubscribe(sub *Subscription) {
+	s.updates.unsubscribe(sub)
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+	"github.com/FrancoRivero2025/go-exercise/internal/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe_ReceivesRefreshedPairs(t *testing.T) {
+	mockProvider := mocks.NewMockMarketDataProvider()
+	service := NewLTPService(mocks.NewMockCache(), mockProvider, time.Minute)
+
+	sub := service.Subscribe([]domain.Pair{"BTC/USD"}, 0)
+	defer service.Unsubscribe(sub)
+
+	usd := createLTP("BTC/USD", "50000.00", time.Now())
+	mockProvider.SetResponse("BTC/USD", usd)
+	mockProvider.SetResponse("BTC/EUR", createLTP("BTC/EUR", "45000.00", time.Now()))
+	service.RefreshPairs(context.Background(), []domain.Pair{"BTC/USD", "BTC/EUR"})
+
+	select {
+	case ev := <-sub.Events:
+		assert.Equal(t, usd, ev.LTP)
+	case <-time.After(time.Second):
+		t.Fatal("expected an update for BTC/USD")
+	}
+	assert.Empty(t, sub.Events, "BTC/EUR was not subscribed")
+}
+
+func TestSubscribe_ResumesAfterLastEventID(t *testing.T) {
+	hub := newUpdateHub()
+	for _, amount := range []string{"1", "2", "3"} {
+		hub.publish(createLTP("BTC/USD", amount, time.Now()))
+	}
+
+	sub := hub.subscribe(nil, 1)
+	defer hub.unsubscribe(sub)
+
+	require.Len(t, sub.Events, 2)
+	assert.Equal(t, uint64(2), (<-sub.Events).ID)
+	assert.Equal(t, uint64(3), (<-sub.Events).ID)
+}
+
+func TestSubscribe_ResetsWhenResumePointIsNoLongerBuffered(t *testing.T) {
+	hub := newUpdateHub()
+	for i := 0; i < replayBuffer+2; i++ {
+		hub.publish(createLTP("BTC/USD", "1", time.Now()))
+	}
+
+	// Event 2 is the last one evicted, so resuming after it loses nothing; after event 1 it would.
+	sub := hub.subscribe(nil, 2)
+	assert.False(t, sub.Reset)
+	assert.Len(t, sub.Events, replayBuffer)
+	hub.unsubscribe(sub)
+
+	sub = hub.subscribe(nil, 1)
+	assert.True(t, sub.Reset)
+	assert.Empty(t, sub.Events, "a partial replay must not be sent")
+	hub.unsubscribe(sub)
+
+	sub = hub.subscribe(nil, uint64(replayBuffer+10))
+	assert.True(t, sub.Reset, "an id this hub never issued cannot be resumed from")
+	hub.unsubscribe(sub)
+}
+
+func TestSubscribe_DropsSlowSubscriber(t *testing.T) {
+	hub := newUpdateHub()
+	slow := hub.subscribe(nil, 0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.publish(createLTP("BTC/USD", "1", time.Now()))
+	}
+
+	assert.True(t, slow.Dropped())
+	for range slow.Events {
+	}
+	hub.unsubscribe(slow)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Candle struct {
+	Time   time.Time       `json:"time"`
+	Open   decimal.Decimal `json:"open"`
+	High   decimal.Decimal `json:"high"`
+	Low    decimal.Decimal `json:"low"`
+	Close  decimal.Decimal `json:"close"`
+	VWAP   decimal.Decimal `json:"vwap"`
+	Volume decimal.Decimal `json:"volume"`
+	Trades int             `json:"trades"`
+}
+
+// CandleSeries is the candles for one pair at one interval (in minutes), oldest first.
+type CandleSeries struct {
+	Pair      Pair      `json:"pair"`
+	Interval  int       `json:"interval"`
+	Candles   []Candle  `json:"candles"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source,omitempty"`
+}
+
+// CandleCache is implemented by caches that can also hold candle series, keyed by pair and interval.
+type CandleCache interface {
+	GetCandles(ctx context.Context, pair Pair, interval int) (CandleSeries, bool)
+	SetCandles(ctx context.Context, series CandleSeries)
+}
+
+// Since returns a copy holding only candles that open at or after t.
+func (s CandleSeries) Since(t time.Time) CandleSeries {
+	if t.IsZero() {
+		return s
+	}
+	for i, c := range s.Candles {
+		if !c.Time.Before(t) {
+			s.Candles = s.Candles[i:]
+			return s
+		}
+	}
+	s.Candles = []Candle{}
+	return s
+}
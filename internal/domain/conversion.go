@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Conversion is an amount of one currency expressed in another using the last traded price of Pair.
+type Conversion struct {
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Amount    decimal.Decimal `json:"amount"`
+	Result    decimal.Decimal `json:"result"`
+	Rate      decimal.Decimal `json:"rate"`
+	Pair      Pair            `json:"pair,omitempty"`
+	Inverted  bool            `json:"inverted"`
+	Error     string          `json:"error,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source,omitempty"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// HistoryStore records prices so they can be looked up after the cache has moved on.
+type HistoryStore interface {
+	Append(ctx context.Context, ltp LTP) error
+	// Range returns the pair's prices with from <= Timestamp <= to, oldest first.
+	Range(ctx context.Context, pair Pair, from, to time.Time) ([]LTP, error)
+	// At returns the last price recorded at or before t.
+	At(ctx context.Context, pair Pair, t time.Time) (LTP, bool, error)
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -8,19 +11,79 @@ import (
 
 type Pair string
 
+var ErrCanceled = errors.New("request canceled")
+
+// Providers prefix LTP errors with these so callers can tell why a fetch failed.
+const (
+	UnsupportedPairPrefix = "unsupported pair"
+	RateLimitedPrefix     = "rate limited"
+)
+
 type LTP struct {
+	Pair      Pair             `json:"pair"`
+	Amount    decimal.Decimal  `json:"amount,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	Source    string           `json:"source,omitempty"`
+	Volume    *decimal.Decimal `json:"volume,omitempty"`
+	Consensus *Consensus       `json:"consensus,omitempty"`
+	Derived   *Derivation      `json:"derived,omitempty"`
+	Stale     bool             `json:"stale,omitempty"`
+	AgeMs     int64            `json:"ageMs,omitempty"`
+}
+
+// Consensus describes how an aggregated price was derived from several exchanges.
+type Consensus struct {
+	Method string  `json:"method"`
+	Quotes []Quote `json:"quotes"`
+}
+
+type Quote struct {
+	Source    string           `json:"source"`
+	Amount    decimal.Decimal  `json:"amount"`
+	Volume    *decimal.Decimal `json:"volume,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+	Used      bool             `json:"used"`
+	Reason    string           `json:"reason,omitempty"`
+}
+
+// Derivation lists the markets a cross rate was synthesized from, in path order.
+type Derivation struct {
+	Legs []Leg `json:"legs"`
+}
+
+// Leg is one market on a derivation path; Inverted means its price was divided rather than multiplied.
+type Leg struct {
 	Pair      Pair            `json:"pair"`
-	Amount    decimal.Decimal `json:"amount,omitempty"`
-	Error     string          `json:"error,omitempty"`
+	Amount    decimal.Decimal `json:"amount"`
+	Inverted  bool            `json:"inverted"`
 	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source,omitempty"`
 }
 
 type Cache interface {
-	Get(pair Pair) (LTP, bool)
-	Set(pair Pair, ltp LTP)
+	Get(ctx context.Context, pair Pair) (LTP, bool)
+	Set(ctx context.Context, pair Pair, ltp LTP)
 	CheckConnectivity() bool
 }
 
+// LastKnownCache is implemented by caches that keep each pair's last good price after its entry expires.
+type LastKnownCache interface {
+	GetLastKnown(ctx context.Context, pair Pair) (LTP, bool)
+}
+
 func (l LTP) IsEmpty() bool {
 	return l.Pair == "" && l.Amount.IsZero() && l.Timestamp.IsZero()
 }
+
+func (l LTP) IsUnsupported() bool {
+	return strings.HasPrefix(l.Error, UnsupportedPairPrefix)
+}
+
+func (l LTP) IsDerived() bool {
+	return l.Derived != nil
+}
+
+func (l LTP) IsRateLimited() bool {
+	return strings.HasPrefix(l.Error, RateLimitedPrefix)
+}
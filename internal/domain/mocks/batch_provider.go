@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/FrancoRivero2025/go-exercise/internal/domain"
+)
+
+type MockBatchProvider struct {
+	*MockMarketDataProvider
+	batches [][]domain.Pair
+}
+
+func NewMockBatchProvider() *MockBatchProvider {
+	return &MockBatchProvider{MockMarketDataProvider: NewMockMarketDataProvider()}
+}
+
+func (m *MockBatchProvider) FetchMany(ctx context.Context, pairs []domain.Pair) []domain.LTP {
+	m.batches = append(m.batches, pairs)
+	out := make([]domain.LTP, len(pairs))
+	for i, pair := range pairs {
+		out[i] = m.responses[pair]
+	}
+	return out
+}
+
+func (m *MockBatchProvider) GetBatches() [][]domain.Pair {
+	return m.batches
+}
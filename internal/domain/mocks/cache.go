@@ -1,35 +1,93 @@
 package mocks
 
 import (
+	"context"
+	"fmt"
 	"sync"
 
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
 )
 
 type MockCache struct {
-	data  map[domain.Pair]domain.LTP
-	mutex sync.RWMutex
+	data    map[domain.Pair]domain.LTP
+	tickers map[domain.Pair]domain.Ticker
+	books   map[domain.Pair]domain.OrderBook
+	candles map[string]domain.CandleSeries
+	mutex   sync.RWMutex
 }
 
 func NewMockCache() *MockCache {
 	return &MockCache{
-		data: make(map[domain.Pair]domain.LTP),
+		data:    make(map[domain.Pair]domain.LTP),
+		tickers: make(map[domain.Pair]domain.Ticker),
+		books:   make(map[domain.Pair]domain.OrderBook),
+		candles: make(map[string]domain.CandleSeries),
 	}
 }
 
-func (m *MockCache) Get(pair domain.Pair) (domain.LTP, bool) {
+func (m *MockCache) Get(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 	ltp, exists := m.data[pair]
 	return ltp, exists
 }
 
-func (m *MockCache) Set(pair domain.Pair, ltp domain.LTP) {
+func (m *MockCache) Set(ctx context.Context, pair domain.Pair, ltp domain.LTP) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	m.data[pair] = ltp
 }
 
+// GetLastKnown returns the stored price if it is a good one; MockCache entries never expire.
+func (m *MockCache) GetLastKnown(ctx context.Context, pair domain.Pair) (domain.LTP, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	ltp, exists := m.data[pair]
+	if !exists || ltp.Error != "" || !ltp.Amount.IsPositive() {
+		return domain.LTP{}, false
+	}
+	return ltp, true
+}
+
+func (m *MockCache) GetTicker(ctx context.Context, pair domain.Pair) (domain.Ticker, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	ticker, exists := m.tickers[pair]
+	return ticker, exists
+}
+
+func (m *MockCache) SetTicker(ctx context.Context, pair domain.Pair, ticker domain.Ticker) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.tickers[pair] = ticker
+}
+
+func (m *MockCache) GetOrderBook(ctx context.Context, pair domain.Pair) (domain.OrderBook, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	book, exists := m.books[pair]
+	return book, exists
+}
+
+func (m *MockCache) SetOrderBook(ctx context.Context, pair domain.Pair, book domain.OrderBook) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.books[pair] = book
+}
+
+func (m *MockCache) GetCandles(ctx context.Context, pair domain.Pair, interval int) (domain.CandleSeries, bool) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	series, exists := m.candles[fmt.Sprintf("%s:%d", pair, interval)]
+	return series, exists
+}
+
+func (m *MockCache) SetCandles(ctx context.Context, series domain.CandleSeries) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.candles[fmt.Sprintf("%s:%d", series.Pair, series.Interval)] = series
+}
+
 func (m *MockCache) CheckConnectivity() bool {
 	return true
 }
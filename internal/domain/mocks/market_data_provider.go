@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"time"
 
 	"github.com/FrancoRivero2025/go-exercise/internal/domain"
@@ -56,7 +57,7 @@ func (m *MockMarketDataProvider) GetCallCount(pair domain.Pair) int {
 	return m.callCount[pair]
 }
 
-func (m *MockMarketDataProvider) Fetch(pair domain.Pair) domain.LTP {
+func (m *MockMarketDataProvider) Fetch(ctx context.Context, pair domain.Pair) domain.LTP {
 	if m.callCount == nil {
 		m.callCount = make(map[domain.Pair]int)
 	}
@@ -67,7 +68,15 @@ func (m *MockMarketDataProvider) Fetch(pair domain.Pair) domain.LTP {
 	}
 	if m.delays != nil {
 		if delay, exists := m.delays[pair]; exists {
-			time.Sleep(delay)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return domain.LTP{
+					Pair:      pair,
+					Error:     domain.ErrCanceled.Error(),
+					Timestamp: time.Now().UTC(),
+				}
+			}
 		}
 	}
 	if m.responses != nil {
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type PriceLevel struct {
+	Price  decimal.Decimal `json:"price"`
+	Volume decimal.Decimal `json:"volume"`
+}
+
+// OrderBook holds the top of the book with bids sorted best-first (highest) and asks best-first (lowest).
+type OrderBook struct {
+	Pair      Pair            `json:"pair"`
+	Bids      []PriceLevel    `json:"bids"`
+	Asks      []PriceLevel    `json:"asks"`
+	MidPrice  decimal.Decimal `json:"midPrice"`
+	Spread    decimal.Decimal `json:"spread"`
+	Error     string          `json:"error,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+	Source    string          `json:"source,omitempty"`
+}
+
+// OrderBookCache is implemented by caches that can also hold order books.
+type OrderBookCache interface {
+	GetOrderBook(ctx context.Context, pair Pair) (OrderBook, bool)
+	SetOrderBook(ctx context.Context, pair Pair, book OrderBook)
+}
+
+// Truncate returns a copy limited to depth levels per side, keeping mid-price and spread.
+func (b OrderBook) Truncate(depth int) OrderBook {
+	if depth > 0 && len(b.Bids) > depth {
+		b.Bids = b.Bids[:depth]
+	}
+	if depth > 0 && len(b.Asks) > depth {
+		b.Asks = b.Asks[:depth]
+	}
+	return b
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Ticker is a full market snapshot for a pair; Today fields cover the current UTC day, 24h fields the rolling window.
+type Ticker struct {
+	Pair        Pair            `json:"pair"`
+	Last        decimal.Decimal `json:"last"`
+	Bid         decimal.Decimal `json:"bid"`
+	Ask         decimal.Decimal `json:"ask"`
+	Spread      decimal.Decimal `json:"spread"`
+	Open        decimal.Decimal `json:"open"`
+	High        decimal.Decimal `json:"high"`
+	Low         decimal.Decimal `json:"low"`
+	VolumeToday decimal.Decimal `json:"volumeToday"`
+	Volume24h   decimal.Decimal `json:"volume24h"`
+	VWAPToday   decimal.Decimal `json:"vwapToday"`
+	VWAP24h     decimal.Decimal `json:"vwap24h"`
+	Error       string          `json:"error,omitempty"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Source      string          `json:"source,omitempty"`
+}
+
+// TickerCache is implemented by caches that can also hold ticker snapshots.
+type TickerCache interface {
+	GetTicker(ctx context.Context, pair Pair) (Ticker, bool)
+	SetTicker(ctx context.Context, pair Pair, ticker Ticker)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+
+	OrderMarket = "market"
+	OrderLimit  = "limit"
+)
+
+type Trade struct {
+	ID        int64           `json:"id"`
+	Price     decimal.Decimal `json:"price"`
+	Volume    decimal.Decimal `json:"volume"`
+	Side      string          `json:"side"`
+	OrderType string          `json:"orderType"`
+	Time      time.Time       `json:"time"`
+}
+
+// TradeBatch is a page of trades; Cursor is what to pass back to fetch the next page.
+type TradeBatch struct {
+	Pair      Pair      `json:"pair"`
+	Trades    []Trade   `json:"trades"`
+	Cursor    string    `json:"cursor,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source,omitempty"`
+}